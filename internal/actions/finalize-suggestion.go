@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -8,10 +9,11 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	"github.com/speakeasy-api/sdk-generation-action/internal/suggestions"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 )
 
-func FinalizeSuggestion() error {
-	g, err := initAction()
+func FinalizeSuggestion(ctx context.Context) error {
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
@@ -40,7 +42,7 @@ func FinalizeSuggestion() error {
 		return err
 	}
 
-	if !cli.IsAtLeastVersion(cli.MinimumSupportedCLIVersion) {
+	if !cli.GetCapabilities().Has(cli.FeatureSuggestions) {
 		return fmt.Errorf("suggestion action requires at least version %s of the speakeasy CLI", cli.MinimumSupportedCLIVersion)
 	}
 
@@ -53,6 +55,9 @@ func FinalizeSuggestion() error {
 	if err != nil {
 		return err
 	}
+	if prNumber != nil {
+		summary.AddPullRequestLink(*prNumber)
+	}
 
 	out := environment.GetCliOutput()
 	if out != "" {
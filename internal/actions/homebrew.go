@@ -0,0 +1,205 @@
+package actions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/sdk-generation-action/internal/configuration"
+	"github.com/speakeasy-api/sdk-generation-action/internal/download"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/git"
+	"github.com/speakeasy-api/sdk-generation-action/internal/homebrew"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+)
+
+// HomebrewFormula generates the Homebrew formula for the latest release of the
+// cli target from its release archives, and commits it to the configured tap.
+func HomebrewFormula(ctx context.Context) error {
+	tap := environment.GetHomebrewTap()
+	if tap == "" {
+		return errors.New("homebrew_tap is required to update the homebrew formula")
+	}
+
+	g, err := initAction(ctx)
+	if err != nil {
+		return err
+	}
+
+	dir, err := getCLITargetDir()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(filepath.Join(environment.GetWorkspace(), "repo", dir))
+	if err != nil {
+		return fmt.Errorf("failed to load config for cli target: %w", err)
+	}
+	if cfg.Config == nil || cfg.LockFile == nil {
+		return fmt.Errorf("config or lock file not found for cli target in %s", dir)
+	}
+
+	version := cfg.LockFile.Management.ReleaseVersion
+	binary := cliBinaryName(cfg.Config.Languages["cli"])
+
+	name := environment.GetHomebrewFormulaName()
+	if name == "" {
+		name = binary
+	}
+
+	description := environment.GetHomebrewFormulaDescription()
+	if description == "" {
+		description = fmt.Sprintf("The %s command line interface", binary)
+	}
+
+	tag := "v" + version
+	assets, err := g.GetReleaseAssets(tag)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := homebrewArtifacts(g, assets)
+	if err != nil {
+		return err
+	}
+
+	formula, err := homebrew.Formula{
+		Name:        name,
+		Description: description,
+		Homepage:    fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), environment.GetRepo()),
+		Version:     version,
+		Binary:      binary,
+		Artifacts:   artifacts,
+	}.Render()
+	if err != nil {
+		return err
+	}
+
+	url, err := g.CommitHomebrewFormula(tap, path.Join("Formula", name+".rb"), formula, name, version, environment.GetMode() == environment.ModePR)
+	if err != nil {
+		return err
+	}
+
+	return setOutputs(map[string]string{
+		"homebrew_formula_url": url,
+	})
+}
+
+func getCLITargetDir() (string, error) {
+	wf, err := configuration.GetWorkflowAndValidateLanguages(true)
+	if err != nil {
+		return "", err
+	}
+
+	targetNames := make([]string, 0, len(wf.Targets))
+	for name := range wf.Targets {
+		targetNames = append(targetNames, name)
+	}
+	sort.Strings(targetNames)
+
+	for _, name := range targetNames {
+		target := wf.Targets[name]
+		if target.Target != "cli" {
+			continue
+		}
+		if specified := environment.SpecifiedTarget(); specified != "" && specified != name {
+			continue
+		}
+
+		dir := "."
+		if target.Output != nil {
+			dir = strings.TrimPrefix(*target.Output, "./")
+		}
+
+		return filepath.Join(environment.GetWorkingDirectory(), dir), nil
+	}
+
+	return "", errors.New("no cli target found in workflow")
+}
+
+func cliBinaryName(cfg config.LanguageConfig) string {
+	for _, key := range []string{"cliName", "packageName"} {
+		if name, ok := cfg.Cfg[key].(string); ok && name != "" {
+			return path.Base(name)
+		}
+	}
+
+	return environment.GetRepo()
+}
+
+// homebrewArtifacts returns the archives of the release that can be installed
+// by Homebrew. Checksums are taken from the release's checksums file if it has
+// one, otherwise the archives are downloaded and hashed.
+func homebrewArtifacts(g *git.Git, assets []*github.ReleaseAsset) ([]homebrew.Artifact, error) {
+	checksums := map[string]string{}
+	for _, asset := range assets {
+		if !strings.HasSuffix(asset.GetName(), "checksums.txt") {
+			continue
+		}
+
+		data, err := readReleaseAsset(g, asset)
+		if err != nil {
+			return nil, err
+		}
+		for name, sum := range download.ParseChecksums(data) {
+			checksums[name] = sum
+		}
+	}
+
+	var artifacts []homebrew.Artifact
+	for _, asset := range assets {
+		os, arch, ok := homebrew.ParseArchiveName(asset.GetName())
+		if !ok {
+			continue
+		}
+
+		sum, ok := checksums[asset.GetName()]
+		if !ok {
+			logging.Info("No checksum found for %s, downloading it", asset.GetName())
+
+			data, err := readReleaseAsset(g, asset)
+			if err != nil {
+				return nil, err
+			}
+			hash := sha256.Sum256(data)
+			sum = hex.EncodeToString(hash[:])
+		}
+
+		artifacts = append(artifacts, homebrew.Artifact{
+			OS:     os,
+			Arch:   arch,
+			URL:    asset.GetBrowserDownloadURL(),
+			SHA256: sum,
+		})
+	}
+
+	if len(artifacts) == 0 {
+		return nil, errors.New("no macOS or Linux archives found in the release assets")
+	}
+
+	return artifacts, nil
+}
+
+func readReleaseAsset(g *git.Git, asset *github.ReleaseAsset) ([]byte, error) {
+	rc, err := g.DownloadReleaseAsset(asset)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release asset %s: %w", asset.GetName(), err)
+	}
+
+	return data, nil
+}
@@ -1,19 +1,20 @@
 package actions
 
 import (
+	"context"
 	"errors"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/git"
 )
 
-func initAction() (*git.Git, error) {
+func initAction(ctx context.Context) (*git.Git, error) {
 	accessToken := environment.GetAccessToken()
 	if accessToken == "" {
 		return nil, errors.New("github access token is required")
 	}
 
-	g := git.New(accessToken)
+	g := git.New(accessToken).WithContext(ctx)
 	if err := g.CloneRepo(); err != nil {
 		return nil, err
 	}
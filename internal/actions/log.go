@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	"gopkg.in/yaml.v3"
 )
 
@@ -37,7 +38,7 @@ func LogActionResult() error {
 
 	key := os.Getenv("SPEAKEASY_API_KEY")
 	if key == "" {
-		fmt.Print("no SPEAKEASY_API_KEY provided.")
+		logging.Info("no SPEAKEASY_API_KEY provided.")
 		return nil
 	}
 
@@ -66,7 +67,7 @@ func LogActionResult() error {
 	languages = strings.ReplaceAll(languages, "\\n", "\n")
 	langs := []string{}
 	if err := yaml.Unmarshal([]byte(languages), &langs); err != nil {
-		fmt.Println("No language provided in github actions config.")
+		logging.Info("No language provided in github actions config.")
 	}
 	if len(langs) > 0 {
 		request.Tags["language"] = langs[0]
@@ -92,9 +93,13 @@ func LogActionResult() error {
 		request.Tags["speakeasy_version"] = os.Getenv("RESOLVED_SPEAKEASY_VERSION")
 	}
 
+	if digest := environment.GetCLIDigest(); digest != "" {
+		request.Tags["speakeasy_cli_sha256"] = digest
+	}
+
 	body, err := json.Marshal(&request)
 	if err != nil {
-		fmt.Print("failure sending log to speakeasy.")
+		logging.Info("failure sending log to speakeasy.")
 		return nil
 	}
 
@@ -105,7 +110,7 @@ func LogActionResult() error {
 
 	req, err := http.NewRequest("POST", baseURL+"/v1/log/proxy", bytes.NewBuffer(body))
 	if err != nil {
-		fmt.Print("failure sending log to speakeasy.")
+		logging.Info("failure sending log to speakeasy.")
 		return nil
 	}
 
@@ -117,14 +122,14 @@ func LogActionResult() error {
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Print("failure sending log to speakeasy.")
+		logging.Info("failure sending log to speakeasy.")
 		return nil
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("failure sending log to speakeasy with status %s.", resp.Status)
+		logging.With("status", resp.Status).Info("failure sending log to speakeasy.")
 	}
 
 	return nil
@@ -1,42 +1,143 @@
 package actions
 
 import (
-	"encoding/base64"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
-	"golang.org/x/exp/rand"
-
+	"github.com/speakeasy-api/sdk-generation-action/internal/git"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
 )
 
 func setOutputs(outputs map[string]string) error {
 	logging.Info("Setting outputs:")
 
-	outputFile := os.Getenv("GITHUB_OUTPUT")
+	if digest := environment.GetCLIDigest(); digest != "" {
+		outputs["speakeasy_cli_sha256"] = digest
+	}
 
-	f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
-	if err != nil {
-		return fmt.Errorf("error opening output file: %w", err)
-	}
-	defer f.Close()
-
-	for k, v := range outputs {
-		if k == "cli_output" {
-			delimiter, err := randomDelimiter()
-			if err != nil {
-				return err
-			}
-
-			err = printAndWriteString(f, fmt.Sprintf("%s<<%s\n%s\n%s\n", k, delimiter, v, delimiter))
-			if err != nil {
-				return err
-			}
-			continue
-		}
-		err = printAndWriteString(f, fmt.Sprintf("%s=%s\n", k, v))
+	var w io.Writer = io.Discard
+	// Don't persist outputs to GH actions if we are in test mode
+	if outputFile := os.Getenv("GITHUB_OUTPUT"); outputFile != "" && !environment.IsTestMode() {
+		f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 		if err != nil {
+			return fmt.Errorf("error opening output file: %w", err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	ow := newOutputWriter(w)
+	if err := ow.SetAll(outputs); err != nil {
+		return err
+	}
+
+	targets, publish := aggregateOutputs(outputs)
+	if err := ow.Set("targets", targets); err != nil {
+		return err
+	}
+
+	return ow.Set("publish", publish)
+}
+
+// targetOutput is an entry of the targets output, a JSON array usable as a
+// job matrix in later jobs.
+type targetOutput struct {
+	Target        string `json:"target"`
+	Directory     string `json:"directory,omitempty"`
+	Regenerated   bool   `json:"regenerated"`
+	Publish       bool   `json:"publish"`
+	ReleaseStatus string `json:"release_status,omitempty"`
+	ReleaseTag    string `json:"release_tag,omitempty"`
+	ReleaseURL    string `json:"release_url,omitempty"`
+}
+
+// aggregateOutputs collects the per-target outputs into the targets output and
+// the publish flags into the publish output. Targets are named as in their
+// outputs, with dashes replaced by underscores.
+func aggregateOutputs(outputs map[string]string) ([]targetOutput, map[string]bool) {
+	byName := map[string]*targetOutput{}
+	target := func(name string) *targetOutput {
+		if t, ok := byName[name]; ok {
+			return t
+		}
+
+		t := &targetOutput{Target: name}
+		byName[name] = t
+		return t
+	}
+
+	publish := map[string]bool{}
+	for key, value := range outputs {
+		switch {
+		case strings.HasSuffix(key, "_regenerated"):
+			target(strings.TrimSuffix(key, "_regenerated")).Regenerated = value == "true"
+		case strings.HasSuffix(key, "_release_status"):
+			target(strings.TrimSuffix(key, "_release_status")).ReleaseStatus = value
+		case strings.HasPrefix(key, "publish_") && key != utils.OutputPublishMCPRegistry:
+			publish[strings.TrimPrefix(key, "publish_")] = value == "true"
+		}
+	}
+
+	var releaseResults []git.ReleaseResult
+	if data := outputs["release_results"]; data != "" {
+		if err := json.Unmarshal([]byte(data), &releaseResults); err != nil {
+			logging.Debug("failed to parse release results: %v", err)
+		}
+	}
+	for _, result := range releaseResults {
+		if t, ok := byName[strings.ReplaceAll(result.Target, "-", "_")]; ok {
+			t.ReleaseTag = result.Tag
+			t.ReleaseURL = result.URL
+		}
+	}
+
+	targets := make([]targetOutput, 0, len(byName))
+	for name, t := range byName {
+		t.Directory = outputs[name+"_directory"]
+		t.Publish = publish[name]
+		targets = append(targets, *t)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Target < targets[j].Target
+	})
+
+	return targets, publish
+}
+
+var outputNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// outputWriter writes step outputs in the format of the GITHUB_OUTPUT file.
+// Multi-line values are written with a delimiter they don't contain.
+type outputWriter struct {
+	w io.Writer
+}
+
+func newOutputWriter(w io.Writer) *outputWriter {
+	return &outputWriter{w: w}
+}
+
+// SetAll writes the outputs in order of their names.
+func (o *outputWriter) SetAll(outputs map[string]string) error {
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := o.Set(k, outputs[k]); err != nil {
 			return err
 		}
 	}
@@ -44,22 +145,78 @@ func setOutputs(outputs map[string]string) error {
 	return nil
 }
 
-func printAndWriteString(f *os.File, out string) error {
-	fmt.Print(out)
-	// Don't persist outputs to GH actions if we are in test mode
-	if !environment.IsTestMode() {
-		if _, err := f.WriteString(out); err != nil {
-			return fmt.Errorf("error writing output: %w", err)
+// Set writes an output. Strings, booleans and numbers are written as is, and
+// any other value as JSON, to be read with fromJSON.
+func (o *outputWriter) Set(name string, value any) error {
+	if !outputNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid output name %q", name)
+	}
+
+	v, err := formatOutput(value)
+	if err != nil {
+		return fmt.Errorf("error formatting output %s: %w", name, err)
+	}
+
+	logging.Info("%s=%s", name, v)
+
+	var entry string
+	if strings.ContainsAny(v, "\r\n") {
+		delimiter, err := randomDelimiter(v)
+		if err != nil {
+			return err
 		}
+
+		entry = fmt.Sprintf("%s<<%s\n%s\n%s\n", name, delimiter, v, delimiter)
+	} else {
+		entry = fmt.Sprintf("%s=%s\n", name, v)
+	}
+
+	if _, err := io.WriteString(o.w, entry); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
 	}
+
 	return nil
 }
 
-func randomDelimiter() (string, error) {
-	b := make([]byte, 15)
-	_, err := rand.Read(b)
+func formatOutput(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.RawMessage:
+		return string(v), nil
+	}
+
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), nil
+	}
+
+	data, err := json.Marshal(value)
 	if err != nil {
-		return "", fmt.Errorf("error generating random delimiter: %w", err)
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func randomDelimiter(value string) (string, error) {
+	for {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", fmt.Errorf("error generating random delimiter: %w", err)
+		}
+
+		delimiter := "ghadelimiter_" + hex.EncodeToString(b)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
 	}
-	return base64.StdEncoding.EncodeToString(b), nil
 }
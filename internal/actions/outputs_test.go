@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputWriter_Set(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{name: "string", value: "v1.2.3", want: "key=v1.2.3\n"},
+		{name: "bool", value: true, want: "key=true\n"},
+		{name: "int", value: 42, want: "key=42\n"},
+		{name: "nil", value: nil, want: "key=\n"},
+		{name: "json", value: map[string]bool{"python": true}, want: "key={\"python\":true}\n"},
+		{name: "multi-line string", value: "line one\nline two", want: "key<<DELIMITER\nline one\nline two\nDELIMITER\n"},
+		{name: "unsupported value", value: func() {}, wantErr: true},
+	}
+
+	delimiterRegex := regexp.MustCompile(`ghadelimiter_[0-9a-f]{32}`)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := newOutputWriter(&buf).Set("key", tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, delimiterRegex.ReplaceAllString(buf.String(), "DELIMITER"))
+		})
+	}
+}
+
+func TestOutputWriter_InvalidName(t *testing.T) {
+	var buf bytes.Buffer
+	w := newOutputWriter(&buf)
+
+	assert.Error(t, w.Set("", "value"))
+	assert.Error(t, w.Set("key<<EOF", "value"))
+	assert.Error(t, w.Set("key=value", "value"))
+	assert.Empty(t, buf.String())
+}
+
+func TestOutputWriter_SetAll(t *testing.T) {
+	var buf bytes.Buffer
+	err := newOutputWriter(&buf).SetAll(map[string]string{
+		"python_regenerated": "true",
+		"branch_name":        "speakeasy-sdk-regen",
+		"commit_hash":        "abc123",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "branch_name=speakeasy-sdk-regen\ncommit_hash=abc123\npython_regenerated=true\n", buf.String())
+}
+
+func TestAggregateOutputs(t *testing.T) {
+	targets, publish := aggregateOutputs(map[string]string{
+		"python_regenerated":            "true",
+		"python_directory":              "sdks/python",
+		"publish_python":                "true",
+		"python_release_status":         "created",
+		"mcp_typescript_regenerated":    "true",
+		"mcp_typescript_directory":      ".",
+		"publish_mcp_typescript":        "false",
+		"publish_mcp_registry":          "true",
+		"target_directory":              "ignored",
+		"release_results":               `[{"target":"python","tag":"v1.0.0","status":"created","url":"https://github.com/org/repo/releases/tag/v1.0.0"}]`,
+		"python_publish_skipped_reason": "",
+	})
+
+	assert.Equal(t, []targetOutput{
+		{Target: "mcp_typescript", Directory: ".", Regenerated: true},
+		{Target: "python", Directory: "sdks/python", Regenerated: true, Publish: true, ReleaseStatus: "created", ReleaseTag: "v1.0.0", ReleaseURL: "https://github.com/org/repo/releases/tag/v1.0.0"},
+	}, targets)
+	assert.Equal(t, map[string]bool{"python": true, "mcp_typescript": false}, publish)
+}
+
+func TestSetOutputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.txt")
+	t.Setenv("GITHUB_OUTPUT", path)
+	t.Setenv("INPUT_MODE", "direct")
+	t.Setenv("SPEAKEASY_CLI_SHA256", "")
+
+	require.NoError(t, setOutputs(map[string]string{
+		"go_regenerated": "true",
+		"go_directory":   "go",
+		"publish_go":     "true",
+	}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "go_directory=go\ngo_regenerated=true\npublish_go=true\n"+
+		"targets=[{\"target\":\"go\",\"directory\":\"go\",\"regenerated\":true,\"publish\":true}]\n"+
+		"publish={\"go\":true}\n", string(data))
+}
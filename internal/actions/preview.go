@@ -0,0 +1,211 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/git"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
+	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+)
+
+// previewCommentMarker identifies the preview comment of a PR. It is followed
+// by the previewMetadata of the comment, so that cleanup knows what was
+// published.
+const previewCommentMarker = "<!-- speakeasy-preview"
+
+type previewMetadata struct {
+	DistTag string `json:"dist_tag"`
+	// Packages maps targets to the names of their preview packages.
+	Packages map[string]string `json:"packages"`
+}
+
+// previewDistTag returns the dist-tag or channel preview versions of the PR are
+// published under.
+func previewDistTag(prNumber int) string {
+	return fmt.Sprintf("pr-%d", prNumber)
+}
+
+// addPreviewVersions sets the preview version outputs of the targets published
+// from the PR, for publishing jobs to push to a preview channel, and comments
+// how to install them on the PR.
+func addPreviewVersions(g *git.Git, pr *github.PullRequest, releaseInfo *releases.ReleasesInfo, outputs map[string]string) error {
+	if releaseInfo == nil || len(releaseInfo.Languages) == 0 {
+		return nil
+	}
+
+	sha := pr.GetHead().GetSHA()
+	metadata, rows := previewVersions(pr.GetNumber(), sha, releaseInfo, outputs)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	outputs["preview_dist_tag"] = metadata.DistTag
+
+	body, err := renderPreviewComment(metadata, sha, rows)
+	if err != nil {
+		return err
+	}
+
+	return upsertPreviewComment(g, pr.GetNumber(), body)
+}
+
+// previewVersions sets the preview version outputs of the targets that publish
+// to a registry supporting previews, returning the metadata and rows of the
+// preview comment.
+func previewVersions(prNumber int, sha string, releaseInfo *releases.ReleasesInfo, outputs map[string]string) (previewMetadata, []string) {
+	metadata := previewMetadata{
+		DistTag:  previewDistTag(prNumber),
+		Packages: map[string]string{},
+	}
+
+	var rows []string
+	for _, lang := range slices.Sorted(maps.Keys(releaseInfo.Languages)) {
+		if outputs[utils.OutputTargetPublish(lang)] != "true" {
+			continue
+		}
+
+		info := releaseInfo.Languages[lang]
+		registry := publishtargets.ForTarget(lang)
+		if !registry.SupportsPreviews() {
+			continue
+		}
+
+		version := registry.PreviewVersion(info.Version, prNumber, sha)
+		outputs[utils.OutputTargetPreviewVersion(lang)] = version
+		metadata.Packages[lang] = info.PackageName
+
+		install := registry.InstallCommand(publishtargets.Package{Name: info.PackageName, Version: version, Path: info.Path})
+		rows = append(rows, fmt.Sprintf("| %s | %s | `%s` |", lang, version, install))
+	}
+
+	return metadata, rows
+}
+
+func renderPreviewComment(metadata previewMetadata, sha string, rows []string) (string, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	return fmt.Sprintf(`%s %s -->
+## 📦 Preview packages
+
+Preview versions of the SDKs in this PR are published from %s under the `+"`%s`"+` tag by the preview publishing jobs.
+
+| Target | Version | Install |
+| --- | --- | --- |
+%s
+`, previewCommentMarker, data, sha, metadata.DistTag, strings.Join(rows, "\n")), nil
+}
+
+// parsePreviewComment returns the metadata of a preview comment, if the comment
+// is one.
+func parsePreviewComment(body string) (*previewMetadata, bool, error) {
+	if !strings.HasPrefix(body, previewCommentMarker) {
+		return nil, false, nil
+	}
+
+	header, _, _ := strings.Cut(strings.TrimPrefix(body, previewCommentMarker), "-->")
+	var metadata previewMetadata
+	if err := json.Unmarshal([]byte(strings.TrimSpace(header)), &metadata); err != nil {
+		return nil, true, fmt.Errorf("failed to parse preview comment metadata: %w", err)
+	}
+
+	return &metadata, true, nil
+}
+
+// upsertPreviewComment updates the preview comment of the PR in place, so that
+// it always describes the latest push.
+func upsertPreviewComment(g *git.Git, prNumber int, body string) error {
+	comments, err := g.ListIssueComments(prNumber)
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		if _, ok, _ := parsePreviewComment(comment.GetBody()); ok {
+			return g.EditIssueComment(comment.GetID(), body)
+		}
+	}
+
+	return g.WriteIssueComment(prNumber, body)
+}
+
+// PreviewCleanup deletes the preview comment of a closed PR and outputs the
+// preview packages it published, so that their dist-tags or channels can be
+// removed.
+func PreviewCleanup(ctx context.Context) error {
+	accessToken := environment.GetAccessToken()
+	if accessToken == "" {
+		return errors.New("github access token is required")
+	}
+
+	prNumber := environment.GetPullRequestNumber()
+	if prNumber == 0 {
+		return errors.New("pr_number is required when not triggered by a pull_request event")
+	}
+
+	g := git.New(accessToken).WithContext(ctx)
+
+	metadata, err := deletePreviewComments(g, prNumber)
+	if err != nil {
+		return err
+	}
+
+	if metadata == nil {
+		logging.Info("No preview packages were published for PR #%d", prNumber)
+		return nil
+	}
+
+	packages, err := json.Marshal(metadata.Packages)
+	if err != nil {
+		return err
+	}
+
+	return setOutputs(map[string]string{
+		"preview_dist_tag": metadata.DistTag,
+		"preview_packages": string(packages),
+	})
+}
+
+// deletePreviewComments deletes the preview comments of the PR, returning the
+// metadata of the last one.
+func deletePreviewComments(g *git.Git, prNumber int) (*previewMetadata, error) {
+	comments, err := g.ListIssueComments(prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata *previewMetadata
+	for _, comment := range comments {
+		m, ok, err := parsePreviewComment(comment.GetBody())
+		if !ok {
+			continue
+		}
+		if err != nil {
+			logging.Info("Failed to parse preview comment %d: %v", comment.GetID(), err)
+		} else {
+			metadata = m
+		}
+
+		if err := g.DeleteIssueComment(comment.GetID()); err != nil {
+			return metadata, err
+		}
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewComment(t *testing.T) {
+	metadata := previewMetadata{
+		DistTag:  previewDistTag(123),
+		Packages: map[string]string{"typescript": "@org/sdk"},
+	}
+
+	body, err := renderPreviewComment(metadata, "abc1234def5678", []string{"| typescript | 1.4.0-pr123.abc1234 | `npm install @org/sdk@1.4.0-pr123.abc1234` |"})
+	require.NoError(t, err)
+	assert.Contains(t, body, "published from abc1234 under the `pr-123` tag")
+	assert.Contains(t, body, "| typescript | 1.4.0-pr123.abc1234 |")
+
+	parsed, ok, err := parsePreviewComment(body)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, &metadata, parsed)
+}
+
+func TestParsePreviewComment(t *testing.T) {
+	_, ok, err := parsePreviewComment("🚀 **Test Report: typescript** — tests passed")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = parsePreviewComment("<!-- speakeasy-preview {invalid -->")
+	assert.Error(t, err)
+	assert.True(t, ok)
+}
+
+func TestPreviewVersions_SkipsTargetsNotPublishing(t *testing.T) {
+	releaseInfo := &releases.ReleasesInfo{Languages: map[string]releases.LanguageReleaseInfo{
+		"typescript": {PackageName: "@org/sdk", Version: "1.4.0"},
+		"python":     {PackageName: "org-sdk", Version: "0.2.0"},
+	}}
+	outputs := map[string]string{"publish_typescript": "true", "publish_python": "false"}
+
+	metadata, rows := previewVersions(123, "abc1234def5678", releaseInfo, outputs)
+	assert.Equal(t, map[string]string{"typescript": "@org/sdk"}, metadata.Packages)
+	assert.Len(t, rows, 1)
+	assert.NotEmpty(t, outputs["typescript_preview_version"])
+	assert.NotContains(t, outputs, "python_preview_version")
+}
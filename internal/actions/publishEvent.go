@@ -1,25 +1,28 @@
 package actions
 
 import (
-	"fmt"
+	"context"
 	"os"
 	"strings"
 
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/git"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	"github.com/speakeasy-api/sdk-generation-action/internal/telemetry"
 )
 
-func PublishEventAction() error {
-	g, err := initAction()
+func PublishEventAction(ctx context.Context) error {
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
 
-	version, err := telemetry.TriggerPublishingEvent(os.Getenv("INPUT_TARGET_DIRECTORY"), os.Getenv("GH_ACTION_RESULT"), os.Getenv("INPUT_REGISTRY_NAME"))
+	version, target, err := telemetry.TriggerPublishingEvent(os.Getenv("INPUT_TARGET_DIRECTORY"), os.Getenv("GH_ACTION_RESULT"), os.Getenv("INPUT_REGISTRY_NAME"), "")
 	if version != "" {
-		if strings.Contains(os.Getenv("GH_ACTION_RESULT"), "success") {
-			if err = g.SetReleaseToPublished(version, os.Getenv("INPUT_TARGET_DIRECTORY")); err != nil {
-				fmt.Println("Failed to set release to published %w", err)
-			}
+		success := strings.Contains(os.Getenv("GH_ACTION_RESULT"), "success")
+		tag := git.ReleaseTag(target, os.Getenv("INPUT_TARGET_DIRECTORY"), version)
+		if err := g.RecordPublishingResult(tag, os.Getenv("INPUT_REGISTRY_NAME"), success, environment.GetActionRunURL(environment.GetRepo())); err != nil {
+			logging.Info("Failed to record publishing result on release: %v", err)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,18 +13,19 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/git"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
 	"github.com/speakeasy-api/sdk-generation-action/internal/run"
 	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
 	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
 )
 
-func Release() error {
+func Release(ctx context.Context) error {
 	accessToken := environment.GetAccessToken()
 	if accessToken == "" {
 		return errors.New("github access token is required")
 	}
 
-	g, err := initAction()
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
@@ -54,7 +56,7 @@ func Release() error {
 		// This searches for files that would be referenced in the GH Action trigger
 		files, err := g.GetCommitedFiles()
 		if err != nil {
-			fmt.Printf("Failed to get commited files: %s\n", err.Error())
+			logging.Info("Failed to get commited files: %s", err.Error())
 		}
 
 		if environment.IsDebugMode() {
@@ -80,13 +82,13 @@ func Release() error {
 		logging.Info("Using gen lockfile to get release info")
 		latestRelease, err = releases.GetReleaseInfoFromGenerationFiles(dir)
 		if err != nil {
-			fmt.Printf("Error getting release info from generation files: %v\n", err)
+			logging.Info("Error getting release info from generation files: %v", err)
 			return err
 		}
 		// targetSpecificReleaseNotes variable is present only if INPUT_ENABLE_SDK_CHANGELOG env is true
 		targetSpecificReleaseNotes, err = releases.GetTargetSpecificReleaseNotes(dir)
 		if err != nil {
-			fmt.Printf("Error getting target specific release notes: %v\n", err)
+			logging.Info("Error getting target specific release notes: %v", err)
 		}
 
 	}
@@ -106,7 +108,13 @@ func Release() error {
 		return err
 	}
 
-	if err := g.CreateRelease(oldReleaseContent, languages, outputs, targetSpecificReleaseNotes); err != nil {
+	skipPublishedVersions(languages, outputs)
+
+	if _, err := g.CreateRelease(oldReleaseContent, languages, outputs, targetSpecificReleaseNotes); err != nil {
+		// Still set outputs so the targets that were released can be published
+		if err := setOutputs(outputs); err != nil {
+			logging.Debug("failed to set outputs: %v", err)
+		}
 		return err
 	}
 
@@ -115,7 +123,7 @@ func Release() error {
 	}
 
 	if os.Getenv("SPEAKEASY_API_KEY") != "" {
-		if err = addCurrentBranchTagging(g, languages); err != nil {
+		if err = addCurrentBranchTagging(ctx, g, languages); err != nil {
 			return errors.Wrap(err, "failed to tag registry images")
 		}
 	}
@@ -177,7 +185,44 @@ func addPublishOutputs(dir string, outputs map[string]string) error {
 	return nil
 }
 
-func addCurrentBranchTagging(g *git.Git, latestRelease map[string]releases.LanguageReleaseInfo) error {
+// skipPublishedVersions stops targets from being published again when their
+// version is already on the package registry, e.g. when a release is rerun.
+func skipPublishedVersions(languages map[string]releases.LanguageReleaseInfo, outputs map[string]string) {
+	if environment.SkipRegistryCheck() {
+		return
+	}
+
+	for lang, info := range languages {
+		outputName := utils.OutputTargetPublish(lang)
+		if outputs[outputName] != "true" {
+			continue
+		}
+
+		registry := publishtargets.ForTarget(lang)
+		if !registry.CanCheckExists() {
+			continue
+		}
+
+		exists, err := registry.PackageExists(publishtargets.Package{
+			Name:    info.PackageName,
+			Version: info.Version,
+			Path:    info.Path,
+		})
+		if err != nil {
+			logging.Info("Unable to check whether %s %s is published to %s, publishing anyway: %v", info.PackageName, info.Version, registry.Name, err)
+			continue
+		}
+
+		if exists {
+			reason := fmt.Sprintf("%s %s is already published to %s", info.PackageName, info.Version, registry.BaseURL())
+			logging.Info("Skipping publishing %s: %s", lang, reason)
+			outputs[outputName] = "false"
+			outputs[utils.OutputTargetPublishSkippedReason(lang)] = reason
+		}
+	}
+}
+
+func addCurrentBranchTagging(ctx context.Context, g *git.Git, latestRelease map[string]releases.LanguageReleaseInfo) error {
 	_, err := cli.Download("latest", g)
 	if err != nil {
 		return err
@@ -248,7 +293,7 @@ func addCurrentBranchTagging(g *git.Git, latestRelease map[string]releases.Langu
 		if isPublished {
 			tags = append(tags, "published")
 		}
-		return cli.Tag(tags, sources, targets)
+		return cli.Tag(ctx, tags, sources, targets)
 	}
 
 	return nil
@@ -1,9 +1,13 @@
 package actions
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+	"github.com/stretchr/testify/assert"
 )
 
 // TestBranchNameSanitizationForOCITags verifies that branch names are properly
@@ -140,3 +144,47 @@ func TestGetDirAndShouldUseReleasesMD(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipPublishedVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/org-sdk/1.0.0" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "npm="+server.URL)
+
+	languages := map[string]releases.LanguageReleaseInfo{
+		"typescript":     {PackageName: "org-sdk", Version: "1.0.0", Path: "typescript"},
+		"mcp-typescript": {PackageName: "org-mcp", Version: "1.0.0", Path: "mcp"},
+		"go":             {PackageName: "github.com/org/repo/go", Version: "1.0.0", Path: "go"},
+	}
+	outputs := map[string]string{
+		"publish_typescript":     "true",
+		"publish_mcp_typescript": "true",
+		"publish_go":             "true",
+	}
+
+	skipPublishedVersions(languages, outputs)
+
+	assert.Equal(t, map[string]string{
+		"publish_typescript":                "false",
+		"typescript_publish_skipped_reason": "org-sdk 1.0.0 is already published to " + server.URL,
+		"publish_mcp_typescript":            "true",
+		"publish_go":                        "true",
+	}, outputs)
+}
+
+func TestSkipPublishedVersions_Disabled(t *testing.T) {
+	t.Setenv("INPUT_SKIP_REGISTRY_CHECK", "true")
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "npm=http://127.0.0.1:0")
+
+	outputs := map[string]string{"publish_typescript": "true"}
+	skipPublishedVersions(map[string]releases.LanguageReleaseInfo{
+		"typescript": {PackageName: "org-sdk", Version: "1.0.0"},
+	}, outputs)
+
+	assert.Equal(t, map[string]string{"publish_typescript": "true"}, outputs)
+}
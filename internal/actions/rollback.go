@@ -0,0 +1,178 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-gen-config/workflow"
+	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
+	"github.com/speakeasy-api/sdk-generation-action/internal/configuration"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/git"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+)
+
+// Rollback undoes the release of a version of a target. The generation commit
+// that was released is reverted on the branch the action was run on, directly
+// or via a PR depending on the mode, and the release is yanked from the
+// releases history. Once the revert is on the branch, the GitHub release and
+// tag are deleted or marked as yanked and the registry tags are moved back to
+// the previous build. In PR mode that happens when the rollback is run again
+// after the PR is merged, which also completes a rollback that failed part way.
+func Rollback(ctx context.Context) error {
+	g, err := initAction(ctx)
+	if err != nil {
+		return err
+	}
+
+	targetName := environment.SpecifiedTarget()
+	if targetName == "" {
+		return errors.New("target is required to roll back a release")
+	}
+
+	version := environment.GetRollbackVersion()
+	if version == "" {
+		return errors.New("rollback_version is required to roll back a release")
+	}
+
+	wf, err := configuration.GetWorkflowAndValidateLanguages(true)
+	if err != nil {
+		return err
+	}
+
+	target, ok := wf.Targets[targetName]
+	if !ok {
+		return fmt.Errorf("target %s not found in workflow", targetName)
+	}
+
+	dir := "."
+	if target.Output != nil {
+		dir = strings.TrimPrefix(*target.Output, "./")
+	}
+	dir = filepath.Join(environment.GetWorkingDirectory(), dir)
+
+	releaseInfo, err := releases.GetReleaseInfoFromGenerationFiles(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get release info for target %s: %w", targetName, err)
+	}
+
+	langInfo := releaseInfo.Languages[target.Target]
+	langInfo.Path = dir
+	langInfo.Version = version
+	langInfo.PreviousVersion = ""
+
+	tag := git.ReleaseTag(target.Target, dir, version)
+	description := fmt.Sprintf("%s v%s", targetName, version)
+	subject := "chore: 🐝 Rollback " + description
+	direct := environment.GetMode() == environment.ModeDirect
+
+	// A rerun finds the rollback already on the branch, pushed directly or
+	// merged from the rollback PR, and only completes the remaining steps
+	rollbackHash, rolledBack, err := g.FindRollbackCommit(subject)
+	if err != nil {
+		return err
+	}
+
+	outputs := map[string]string{}
+	if rolledBack {
+		logging.Info("%s was already rolled back in commit %s", description, rollbackHash)
+		outputs["commit_hash"] = rollbackHash
+	} else {
+		// Resolve the released commit before anything is deleted, so a failed revert leaves the release untouched
+		commitHash, err := g.ResolveTagCommit(tag)
+		if err != nil {
+			return err
+		}
+
+		logging.Info("Rolling back %s v%s released from commit %s", targetName, version, commitHash)
+
+		branchName := ""
+		if !direct {
+			branchName = fmt.Sprintf("speakeasy-rollback-%s-v%s", environment.SanitizeBranchName(targetName), version)
+		}
+
+		if err := g.RevertCommit(commitHash, branchName); err != nil {
+			return err
+		}
+
+		releasesDir, err := getReleasesDir()
+		if err != nil {
+			return err
+		}
+
+		if err := releases.YankRelease(releasesDir, target.Target, langInfo); err != nil {
+			return fmt.Errorf("failed to record yanked release: %w", err)
+		}
+
+		hash, err := g.CommitAndPushRevert(fmt.Sprintf("%s\n\nReverts %s", subject, commitHash), branchName, direct)
+		if err != nil {
+			return err
+		}
+
+		if !direct {
+			pr, err := g.CreateRollbackPR(branchName, description, fmt.Sprintf("Reverts the generation commit %s released as `%s`.\n\nThe release and its registry tags are left in place until this PR is merged. Run the rollback again once it is merged to yank them.", commitHash, tag))
+			if err != nil {
+				return err
+			}
+
+			// Closing the PR instead must leave the generation commit with its release
+			logging.Info("Rollback PR: %s", pr.GetHTMLURL())
+			logging.Info("The release %s and its registry tags are left in place until the rollback PR is merged. Run the rollback again once it is merged to yank them", tag)
+			outputs["branch_name"] = branchName
+
+			return setOutputs(outputs)
+		}
+
+		outputs["commit_hash"] = hash
+	}
+
+	if environment.ShouldDeleteRolledBackRelease() {
+		err = g.DeleteReleaseAndTag(tag)
+	} else {
+		err = g.MarkReleaseYanked(tag, fmt.Sprintf("This release was rolled back on %s.", environment.GetInvokeTime().Format("2006-01-02")))
+	}
+	if err != nil {
+		return err
+	}
+
+	if os.Getenv("SPEAKEASY_API_KEY") != "" {
+		if err := rollbackRegistryTags(ctx, g, wf, targetName); err != nil {
+			return fmt.Errorf("failed to tag registry images: %w", err)
+		}
+	}
+
+	return setOutputs(outputs)
+}
+
+// rollbackRegistryTags moves the branch and published registry tags back to
+// the builds recorded in the reverted workflow lockfile.
+func rollbackRegistryTags(ctx context.Context, g *git.Git, wf *workflow.Workflow, targetName string) error {
+	target := wf.Targets[targetName]
+
+	var sources, targets []string
+	if source, ok := wf.Sources[target.Source]; ok && source.Registry != nil {
+		sources = append(sources, target.Source)
+	}
+	if target.CodeSamples != nil && target.CodeSamples.Registry != nil {
+		targets = append(targets, targetName)
+	}
+	if len(sources) == 0 && len(targets) == 0 {
+		return nil
+	}
+
+	if _, err := cli.Download(environment.GetPinnedSpeakeasyVersion(), g); err != nil {
+		return err
+	}
+
+	tags := []string{environment.SanitizeBranchName(strings.TrimPrefix(environment.GetRef(), "refs/heads/"))}
+	if target.IsPublished() {
+		tags = append(tags, "published")
+	}
+
+	return cli.Tag(ctx, tags, sources, targets)
+}
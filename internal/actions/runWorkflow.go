@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/configuration"
 	"github.com/speakeasy-api/sdk-generation-action/internal/git"
 	"github.com/speakeasy-api/sdk-generation-action/internal/run"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
@@ -22,14 +24,17 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
 )
 
-func RunWorkflow() error {
-	g, err := initAction()
+func RunWorkflow(ctx context.Context) error {
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
 
 	if !environment.SkipCompile() {
-		if err := SetupEnvironment(); err != nil {
+		endGroup := logging.Group("Setup environment")
+		err := SetupEnvironment(ctx)
+		endGroup()
+		if err != nil {
 			return fmt.Errorf("failed to setup environment: %w", err)
 		}
 	} else {
@@ -37,7 +42,9 @@ func RunWorkflow() error {
 	}
 
 	// The top-level CLI can always use latest. The CLI itself manages pinned versions.
+	endGroup := logging.Group("Install Speakeasy CLI")
 	resolvedVersion, err := cli.Download("latest", g)
+	endGroup()
 	if err != nil {
 		return err
 	}
@@ -96,7 +103,11 @@ func RunWorkflow() error {
 		os.Setenv("SPEAKEASY_ACTIVE_BRANCH", branchName)
 	}
 
-	runRes, outputs, err := run.Run(g, pr, wf)
+	runRes, outputs, err := run.Run(ctx, g, pr, wf)
+	if runRes != nil {
+		summary.AddLink("Linting report", runRes.LintingReportURL)
+		summary.AddLink("Changes report", runRes.ChangesReportURL)
+	}
 	if err != nil {
 		if err := setOutputs(outputs); err != nil {
 			logging.Debug("failed to set outputs: %v", err)
@@ -147,7 +158,7 @@ func RunWorkflow() error {
 			LanguagesGenerated: map[string]releases.GenerationInfo{},
 		}
 
-		for _, supportedTargetName := range cli.GetSupportedTargetNames() {
+		for _, supportedTargetName := range cli.GetCapabilities().SupportedTargets {
 			langGenInfo, ok := runRes.GenInfo.Languages[supportedTargetName]
 			if ok && outputs[utils.OutputTargetRegenerated(supportedTargetName)] == "true" {
 				anythingRegenerated = true
@@ -190,6 +201,10 @@ func RunWorkflow() error {
 		}
 	}
 
+	if !anythingRegenerated && !sourcesOnly {
+		summary.SetNotRegeneratedReason("Regenerating the targets did not result in any changes.")
+	}
+
 	outputs["resolved_speakeasy_version"] = resolvedVersion
 	if sourcesOnly {
 		if _, err := g.CommitAndPush("", resolvedVersion, "", environment.ActionRunWorkflow, sourcesOnly, nil); err != nil {
@@ -203,7 +218,7 @@ func RunWorkflow() error {
 		return nil
 	}
 
-	if err := finalize(finalizeInputs{
+	if err := finalize(ctx, finalizeInputs{
 		Outputs:              outputs,
 		BranchName:           branchName,
 		AnythingRegenerated:  anythingRegenerated,
@@ -257,12 +272,14 @@ type finalizeInputs struct {
 }
 
 // Sets outputs and creates or adds releases info
-func finalize(inputs finalizeInputs) error {
+func finalize(ctx context.Context, inputs finalizeInputs) error {
 	// If nothing was regenerated, we don't need to do anything
 	if !inputs.AnythingRegenerated && !inputs.SourcesOnly {
 		return nil
 	}
 
+	defer logging.Group("Finalize")()
+
 	branchName, err := inputs.Git.FindAndCheckoutBranch(inputs.BranchName)
 	if err != nil {
 		return err
@@ -302,6 +319,13 @@ func finalize(inputs finalizeInputs) error {
 
 		if pr != nil {
 			os.Setenv("GH_PULL_REQUEST", *pr.URL)
+			summary.AddPullRequestLink(pr.GetNumber())
+
+			if environment.PreviewVersions() && !inputs.SourcesOnly {
+				if err := addPreviewVersions(inputs.Git, pr, inputs.currentRelease, inputs.Outputs); err != nil {
+					logging.Info("Failed to add preview versions: %v", err)
+				}
+			}
 		}
 
 		// If we are in PR mode and testing should be triggered by this PR we will attempt to fire an empty commit from our app so trigger github actions checks
@@ -309,8 +333,8 @@ func finalize(inputs finalizeInputs) error {
 		// If the customer has manually set up a PR_CREATION_PAT we will not do this
 		if inputs.GenInfo != nil && inputs.GenInfo.HasTestingEnabled && os.Getenv("PR_CREATION_PAT") == "" {
 			sanitizedBranchName := strings.TrimPrefix(branchName, "refs/heads/")
-			if err := cli.FireEmptyCommit(os.Getenv("GITHUB_REPOSITORY_OWNER"), git.GetRepo(), sanitizedBranchName); err != nil {
-				fmt.Println("Failed to create empty commit to trigger testing workflow", err)
+			if err := cli.FireEmptyCommit(ctx, os.Getenv("GITHUB_REPOSITORY_OWNER"), git.GetRepo(), sanitizedBranchName); err != nil {
+				logging.Info("Failed to create empty commit to trigger testing workflow: %v", err)
 			}
 		}
 
@@ -345,6 +369,7 @@ func finalize(inputs finalizeInputs) error {
 		if err != nil {
 			return err
 		}
+		summary.AddCommitLink(commitHash)
 
 		// Skip releasing and tagging when configured to do so or when triggered by PR events
 		if environment.ShouldSkipReleasing() {
@@ -354,7 +379,7 @@ func finalize(inputs finalizeInputs) error {
 		}
 
 		if !inputs.SourcesOnly {
-			if err := inputs.Git.CreateRelease(oldReleaseInfo, languages, inputs.Outputs, targetSpecificReleaseNotes); err != nil {
+			if _, err := inputs.Git.CreateRelease(oldReleaseInfo, languages, inputs.Outputs, targetSpecificReleaseNotes); err != nil {
 				return err
 			}
 		}
@@ -362,7 +387,7 @@ func finalize(inputs finalizeInputs) error {
 		inputs.Outputs["commit_hash"] = commitHash
 
 		// add merging branch registry tag
-		if err = addDirectModeBranchTagging(); err != nil {
+		if err = addDirectModeBranchTagging(ctx); err != nil {
 			return errors.Wrap(err, "failed to tag registry images")
 		}
 
@@ -371,7 +396,7 @@ func finalize(inputs finalizeInputs) error {
 	return nil
 }
 
-func addDirectModeBranchTagging() error {
+func addDirectModeBranchTagging(ctx context.Context) error {
 	wf, err := configuration.GetWorkflowAndValidateLanguages(true)
 	if err != nil {
 		return err
@@ -411,7 +436,7 @@ func addDirectModeBranchTagging() error {
 		if isPublished {
 			tags = append(tags, "published")
 		}
-		return cli.Tag(tags, sources, targets)
+		return cli.Tag(ctx, tags, sources, targets)
 	}
 
 	return nil
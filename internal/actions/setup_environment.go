@@ -1,10 +1,11 @@
 package actions
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
 )
 
 // SetupEnvironment will install runtime environment dependencies.
@@ -12,18 +13,18 @@ import (
 // For example if pnpm is desired instead of npm for target compilation and
 // publishing, then an input (pnpm_version in this case) should be set to a
 // non-empty value and this logic will install the dependency.
-func SetupEnvironment() error {
-	if err := installPoetry(); err != nil {
+func SetupEnvironment(ctx context.Context) error {
+	if err := installPoetry(ctx); err != nil {
 		return err
 	}
 
-	if err := installUv(); err != nil {
+	if err := installUv(ctx); err != nil {
 		return err
 	}
 
 	if pnpmVersion := environment.GetPnpmVersion(); pnpmVersion != "" {
 		pnpmPackageSpec := "pnpm@" + pnpmVersion
-		cmd := exec.Command("npm", "install", "-g", pnpmPackageSpec)
+		cmd := process.Command(ctx, process.PhaseSetup, "npm", "install", "-g", pnpmPackageSpec)
 
 		if err := cmd.Run(); err != nil {
 			return fmt.Errorf("error installing %s: %w", pnpmPackageSpec, err)
@@ -35,14 +36,14 @@ func SetupEnvironment() error {
 
 // Installs poetry using pipx. If the INPUT_POETRY_VERSION environment variable
 // is set, it will install that version.
-func installPoetry() error {
+func installPoetry(ctx context.Context) error {
 	poetrySpec := "poetry"
 
 	if poetryVersion := environment.GetPoetryVersion(); poetryVersion != "" {
 		poetrySpec = "poetry==" + poetryVersion
 	}
 
-	cmd := exec.Command("pipx", "install", "--global", poetrySpec)
+	cmd := process.Command(ctx, process.PhaseSetup, "pipx", "install", "--global", poetrySpec)
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("error installing poetry: %w", err)
@@ -53,14 +54,14 @@ func installPoetry() error {
 
 // Installs uv using pipx. If the INPUT_UV_VERSION environment variable
 // is set, it will install that version.
-func installUv() error {
+func installUv(ctx context.Context) error {
 	uvSpec := "uv"
 
 	if uvVersion := environment.GetUvVersion(); uvVersion != "" {
 		uvSpec = "uv==" + uvVersion
 	}
 
-	cmd := exec.Command("pipx", "install", "--global", uvSpec)
+	cmd := process.Command(ctx, process.PhaseSetup, "pipx", "install", "--global", uvSpec)
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("error installing uv: %w", err)
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
@@ -10,8 +11,8 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/suggestions"
 )
 
-func Suggest() error {
-	g, err := initAction()
+func Suggest(ctx context.Context) error {
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
@@ -21,11 +22,11 @@ func Suggest() error {
 		return err
 	}
 
-	if !cli.IsAtLeastVersion(cli.MinimumSupportedCLIVersion) {
+	if !cli.GetCapabilities().Has(cli.FeatureSuggestions) {
 		return fmt.Errorf("suggestion action requires at least version %s of the speakeasy CLI", cli.MinimumSupportedCLIVersion)
 	}
 
-	docPath, _, err := document.GetOpenAPIFileInfo()
+	docPath, _, err := document.GetOpenAPIFileInfo(ctx)
 	if err != nil {
 		return err
 	}
@@ -53,7 +54,7 @@ func Suggest() error {
 		}
 	}()
 
-	out, err := suggestions.Suggest(docPath, environment.GetMaxSuggestions())
+	out, err := suggestions.Suggest(ctx, docPath, environment.GetMaxSuggestions())
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/configuration"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
@@ -9,8 +10,8 @@ import (
 	"golang.org/x/exp/maps"
 )
 
-func Tag() error {
-	g, err := initAction()
+func Tag(ctx context.Context) error {
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
@@ -36,5 +37,5 @@ func Tag() error {
 		logging.Info("No sources or targets specified, using all sources and targets from workflow")
 	}
 
-	return cli.Tag(tags, sources, targets)
+	return cli.Tag(ctx, tags, sources, targets)
 }
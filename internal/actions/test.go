@@ -13,6 +13,8 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/configuration"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/git"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 	"github.com/speakeasy-api/sdk-generation-action/internal/telemetry"
 	"golang.org/x/exp/slices"
 )
@@ -25,12 +27,12 @@ type TestReport struct {
 }
 
 func Test(ctx context.Context) error {
-	g, err := initAction()
+	g, err := initAction(ctx)
 	if err != nil {
 		return err
 	}
 
-	if err := SetupEnvironment(); err != nil {
+	if err := SetupEnvironment(ctx); err != nil {
 		return fmt.Errorf("failed to setup environment: %w", err)
 	}
 
@@ -47,7 +49,7 @@ func Test(ctx context.Context) error {
 	var prNumber *int
 	_, number, err := g.GetChangedFilesForPRorBranch()
 	if err != nil {
-		fmt.Printf("Failed to get PR info: %s\n", err.Error())
+		logging.Info("Failed to get PR info: %s", err.Error())
 	}
 	prNumber = number
 
@@ -61,7 +63,7 @@ func Test(ctx context.Context) error {
 		outDir := filepath.Join(environment.GetRepoPath(), targetOutput)
 		cfg, err := config.Load(outDir)
 		if err != nil {
-			fmt.Printf("Failed to load config for target %s: %s\n", name, err.Error())
+			logging.With("target", name).Info("Failed to load config for target: %s", err.Error())
 			continue
 		}
 		if cfg.LockFile != nil {
@@ -76,7 +78,7 @@ func Test(ctx context.Context) error {
 		// No target specified — discover targets from changed files in the PR
 		files, _, err := g.GetChangedFilesForPRorBranch()
 		if err != nil {
-			fmt.Printf("Failed to get changed files: %s\n", err.Error())
+			logging.Info("Failed to get changed files: %s", err.Error())
 		}
 
 		for _, file := range files {
@@ -103,7 +105,7 @@ func Test(ctx context.Context) error {
 		}
 	}
 	if len(testedTargets) == 0 {
-		fmt.Println("No target was provided ... skipping tests")
+		logging.Info("No target was provided ... skipping tests")
 		return nil
 	}
 
@@ -113,7 +115,7 @@ func Test(ctx context.Context) error {
 	testReports := make(map[string]TestReport)
 	var errs []error
 	for _, target := range testedTargets {
-		err := cli.Test(target)
+		err := cli.Test(ctx, target)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -122,25 +124,30 @@ func Test(ctx context.Context) error {
 		if genLockID, ok := targetLockIDs[target]; ok && genLockID != "" {
 			testReportURL = formatTestReportURL(ctx, genLockID)
 		} else {
-			fmt.Printf("No gen.lock ID found for target %s (available targets: %v)\n", target, targetLockIDs)
+			logging.With("target", target).Info("No gen.lock ID found for target (available targets: %v)", targetLockIDs)
 		}
 
 		if testReportURL == "" {
-			fmt.Printf("No test report URL could be formed for target %s\n", target)
+			logging.With("target", target).Info("No test report URL could be formed for target")
 		}
 
 		testReports[target] = TestReport{
 			Success: err == nil,
 			URL:     testReportURL,
 		}
+		summary.AddTestResult(summary.TestResult{
+			Target:    target,
+			Passed:    err == nil,
+			ReportURL: testReportURL,
+		})
 	}
 
 	if len(testReports) > 0 && prNumber != nil {
 		if err := writeTestReportComment(g, prNumber, testReports); err != nil {
-			fmt.Printf("Failed to write test report comment: %s\n", err.Error())
+			logging.Info("Failed to write test report comment: %s", err.Error())
 		}
 	} else if len(testReports) > 0 && prNumber == nil {
-		fmt.Println("Skipping test report PR comment: could not determine PR number")
+		logging.Info("Skipping test report PR comment: could not determine PR number")
 	}
 
 	if len(errs) > 0 {
@@ -182,7 +189,7 @@ func writeTestReportComment(g *git.Git, prNumber *int, testReports map[string]Te
 
 	currentPRComments, err := g.ListIssueComments(*prNumber)
 	if err != nil {
-		fmt.Printf("Failed to list PR comments: %s\n", err.Error())
+		logging.Info("Failed to list PR comments: %s", err.Error())
 	}
 
 	// Each target gets its own comment to avoid race conditions when
@@ -194,7 +201,7 @@ func writeTestReportComment(g *git.Git, prNumber *int, testReports map[string]Te
 		for _, comment := range currentPRComments {
 			if strings.Contains(comment.GetBody(), targetHeader) {
 				if err := g.DeleteIssueComment(comment.GetID()); err != nil {
-					fmt.Printf("Failed to delete existing test report comment for %s: %s\n", target, err.Error())
+					logging.With("target", target).Info("Failed to delete existing test report comment: %s", err.Error())
 				}
 			}
 		}
@@ -216,7 +223,7 @@ func writeTestReportComment(g *git.Git, prNumber *int, testReports map[string]Te
 		}
 
 		if err := g.WriteIssueComment(*prNumber, body); err != nil {
-			fmt.Printf("Failed to write test report comment for %s: %s\n", target, err.Error())
+			logging.With("target", target).Info("Failed to write test report comment: %s", err.Error())
 		}
 	}
 
@@ -0,0 +1,261 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/download"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+)
+
+// ChecksumsFile is the name of the manifest listing the SHA256 digest of every
+// packaged artifact, in the format produced by sha256sum.
+const ChecksumsFile = "SHA256SUMS"
+
+type packager struct {
+	// commands are run in order in the target directory. The output directory
+	// is substituted for the "{out}" placeholder.
+	commands [][]string
+	// outputs are globs, relative to the target directory, matching artifacts
+	// for package managers that cannot write directly to the output directory.
+	outputs []string
+}
+
+func getPackager(lang string) *packager {
+	switch lang {
+	case "typescript", "mcp-typescript":
+		return &packager{
+			commands: [][]string{
+				{"npm", "install"},
+				{"npm", "pack", "--pack-destination", "{out}"},
+			},
+		}
+	case "python":
+		return &packager{
+			commands: [][]string{
+				{"pipx", "run", "build", "--sdist", "--wheel", "--outdir", "{out}"},
+			},
+		}
+	case "java":
+		return &packager{
+			commands: [][]string{
+				{"gradle", "build", "-x", "test"},
+			},
+			outputs: []string{"build/libs/*.jar"},
+		}
+	case "csharp":
+		return &packager{
+			commands: [][]string{
+				{"dotnet", "pack", "--configuration", "Release", "--output", "{out}"},
+			},
+		}
+	case "ruby":
+		return &packager{
+			commands: [][]string{
+				{"gem", "build"},
+			},
+			outputs: []string{"*.gem"},
+		}
+	case "php":
+		return &packager{
+			commands: [][]string{
+				{"composer", "archive", "--format=zip", "--dir={out}"},
+			},
+		}
+	}
+
+	return nil
+}
+
+// IsSupported returns true if artifacts can be packaged for the given target.
+func IsSupported(lang string) bool {
+	return getPackager(lang) != nil
+}
+
+// Package runs the standard pack command for the target language against a
+// temporary copy of dir, so the checkout is left untouched, and writes the
+// resulting artifacts, along with a SHA256SUMS manifest, to outDir. The
+// returned paths include the manifest.
+func Package(ctx context.Context, lang, dir, outDir string) ([]string, error) {
+	p := getPackager(lang)
+	if p == nil {
+		return nil, fmt.Errorf("packaging artifacts is not supported for %s targets", lang)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	workDir, err := os.MkdirTemp("", "speakeasy-package-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create packaging directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := copyDir(dir, workDir); err != nil {
+		return nil, err
+	}
+
+	if lang == "java" {
+		if _, err := os.Stat(filepath.Join(workDir, "gradlew")); err == nil {
+			p.commands[0][0] = "./gradlew"
+		}
+	}
+
+	for _, command := range p.commands {
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i] = strings.ReplaceAll(arg, "{out}", outDir)
+		}
+
+		logging.Info("Packaging %s artifacts: %s", lang, strings.Join(args, " "))
+
+		cmd := process.Command(ctx, process.PhaseRelease, args[0], args[1:]...)
+		cmd.Dir = workDir
+		cmd.Env = os.Environ()
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("error running command: %s - %w", strings.Join(args, " "), err)
+		}
+	}
+
+	for _, pattern := range p.outputs {
+		matches, err := filepath.Glob(filepath.Join(workDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %s: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			if err := copyFile(match, filepath.Join(outDir, filepath.Base(match))); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	files, err := listFiles(outDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no artifacts were produced for %s target in %s", lang, dir)
+	}
+
+	checksums, err := WriteChecksums(files, filepath.Join(outDir, ChecksumsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(files, checksums), nil
+}
+
+// WriteChecksums writes a sha256sum compatible manifest of the given files to
+// outPath and returns its path.
+func WriteChecksums(files []string, outPath string) (string, error) {
+	lines := make([]string, 0, len(files))
+	for _, file := range files {
+		digest, err := download.SHA256File(file)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s\n", digest, filepath.Base(file)))
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i][65:] < lines[j][65:]
+	})
+
+	if err := os.WriteFile(outPath, []byte(strings.Join(lines, "")), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write checksums file: %w", err)
+	}
+
+	return outPath, nil
+}
+
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifacts directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == ChecksumsFile {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// copyDir copies the contents of src into dst, preserving file modes and
+// symlinks. The .git directory is skipped as no packager needs it.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir() && d.Name() == ".git":
+			return filepath.SkipDir
+		case d.IsDir():
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+			return nil
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("failed to read link %s: %w", path, err)
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return fmt.Errorf("failed to create link %s: %w", target, err)
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		if err := copyFile(path, target); err != nil {
+			return err
+		}
+		return os.Chmod(target, info.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open artifact %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create artifact %s: %w", dst, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy artifact %s: %w", src, err)
+	}
+
+	return nil
+}
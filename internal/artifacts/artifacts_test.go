@@ -0,0 +1,82 @@
+package artifacts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	b := filepath.Join(dir, "b.whl")
+	a := filepath.Join(dir, "a.tar.gz")
+	require.NoError(t, os.WriteFile(b, []byte("b"), 0o644))
+	require.NoError(t, os.WriteFile(a, []byte("a"), 0o644))
+
+	out, err := WriteChecksums([]string{b, a}, filepath.Join(dir, ChecksumsFile))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb  a.tar.gz\n"+
+			"3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d  b.whl\n",
+		string(data))
+}
+
+func TestPackage(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		wantErr string
+	}{
+		{name: "go is unsupported", lang: "go", wantErr: "not supported for go targets"},
+		{name: "terraform is unsupported", lang: "terraform", wantErr: "not supported for terraform targets"},
+		{name: "ruby without gemspec fails", lang: "ruby", wantErr: "error running command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PATH", t.TempDir())
+
+			_, err := Package(context.Background(), tt.lang, t.TempDir(), t.TempDir())
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPackage_LeavesCheckoutUntouched(t *testing.T) {
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "gem"), []byte("#!/bin/sh\nmkdir -p vendor && echo gem > acme-1.2.3.gem\n"), 0o755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "acme.gemspec"), []byte("spec"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+
+	files, err := Package(context.Background(), "ruby", dir, t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "acme-1.2.3.gem", filepath.Base(files[0]))
+	assert.Equal(t, ChecksumsFile, filepath.Base(files[1]))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{".git", "acme.gemspec"}, names)
+}
+
+func TestIsSupported(t *testing.T) {
+	for _, lang := range []string{"typescript", "mcp-typescript", "python", "java", "csharp", "ruby", "php"} {
+		assert.True(t, IsSupported(lang), lang)
+	}
+	for _, lang := range []string{"go", "terraform", "cli", "swift"} {
+		assert.False(t, IsSupported(lang), lang)
+	}
+}
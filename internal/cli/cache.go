@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/download"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+)
+
+// maxCachedVersions is the number of most recently used versions kept in the
+// cache, so that pinned versions of several workflows stay cached.
+const maxCachedVersions = 5
+
+const digestFile = "speakeasy.sha256"
+
+// toolCache caches extracted speakeasy cli binaries, along with the release
+// archives they were extracted from, under
+// <dir>/speakeasy/<version>/<os>-<arch>, the layout of the runner tool cache,
+// so that the directory can be restored by actions/cache.
+type toolCache struct {
+	root string
+}
+
+// newToolCache returns the cache in the cli_cache_dir, or nil if there is no
+// cache directory.
+func newToolCache() *toolCache {
+	dir := environment.GetCLICacheDir()
+	if dir == "" {
+		return nil
+	}
+
+	return &toolCache{root: filepath.Join(dir, "speakeasy")}
+}
+
+func (c *toolCache) dir(version string) string {
+	return filepath.Join(c.root, version, runtime.GOOS+"-"+runtime.GOARCH)
+}
+
+func (c *toolCache) binary(version string) string {
+	return filepath.Join(c.dir(version), "speakeasy")
+}
+
+// lookup returns the cached binary of the version. Entries whose binary
+// doesn't match the digest recorded with it are removed. The recorded digest
+// only guards against corrupted entries, as it is restored along with the
+// binary, so a cached binary is only trusted with verify_cli off.
+func (c *toolCache) lookup(version string) (string, bool) {
+	binary := c.binary(version)
+	if _, err := os.Stat(binary); err != nil {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir(version), digestFile))
+	if err != nil {
+		c.remove(version, "no recorded digest")
+		return "", false
+	}
+
+	digest, err := download.SHA256File(binary)
+	if err != nil || digest != download.ParseChecksums(data)["speakeasy"] {
+		c.remove(version, "digest mismatch")
+		return "", false
+	}
+
+	c.touch(version)
+
+	return binary, true
+}
+
+// archive returns the cached release archive with the name the binary of the
+// version was extracted from.
+func (c *toolCache) archive(version, name string) (string, bool) {
+	archive := filepath.Join(c.dir(version), filepath.Base(name))
+	if info, err := os.Lstat(archive); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+
+	return archive, true
+}
+
+// store keeps the release archive the binary of the version cached in its
+// directory was extracted from, so that it can be verified again, and records
+// the digest of the binary, then prunes the least recently used versions.
+func (c *toolCache) store(version, archive, name string) error {
+	if err := copyFile(archive, filepath.Join(c.dir(version), filepath.Base(name))); err != nil {
+		return fmt.Errorf("failed to cache speakeasy cli archive: %w", err)
+	}
+
+	digest, err := download.SHA256File(c.binary(version))
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(c.dir(version), digestFile), []byte(fmt.Sprintf("%s  speakeasy\n", digest)), 0o644); err != nil {
+		return fmt.Errorf("failed to record speakeasy cli digest: %w", err)
+	}
+
+	c.touch(version)
+
+	return c.prune(version)
+}
+
+// remove drops the cached version, which can't be used.
+func (c *toolCache) remove(version, reason string) {
+	logging.Info("Removing invalid cached speakeasy cli %s: %s", version, reason)
+	_ = os.RemoveAll(c.dir(version))
+}
+
+// touch marks the version as used, as the modification time of its directory
+// orders versions for pruning.
+func (c *toolCache) touch(version string) {
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(c.root, version), now, now)
+}
+
+func (c *toolCache) prune(current string) error {
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return fmt.Errorf("failed to read speakeasy cli cache: %w", err)
+	}
+
+	type cached struct {
+		version string
+		usedAt  time.Time
+	}
+
+	var versions []cached
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == current {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		versions = append(versions, cached{version: entry.Name(), usedAt: info.ModTime()})
+	}
+
+	slices.SortFunc(versions, func(a, b cached) int {
+		return b.usedAt.Compare(a.usedAt)
+	})
+
+	// The current version is always kept
+	for i, v := range versions {
+		if i < maxCachedVersions-1 {
+			continue
+		}
+
+		logging.Info("Pruning cached speakeasy cli %s", v.version)
+		if err := os.RemoveAll(filepath.Join(c.root, v.version)); err != nil {
+			return fmt.Errorf("failed to prune cached speakeasy cli %s: %w", v.version, err)
+		}
+	}
+
+	return nil
+}
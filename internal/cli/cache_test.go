@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/download"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFakeCLI(t *testing.T, path, version string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("#!/bin/sh\necho 'speakeasy version %s'\n", version)), 0o755))
+}
+
+func TestToolCache_Lookup(t *testing.T) {
+	t.Setenv("INPUT_CLI_CACHE_DIR", t.TempDir())
+	cache := newToolCache()
+	require.NotNil(t, cache)
+
+	_, ok := cache.lookup("v1.500.0")
+	assert.False(t, ok)
+
+	archive := writeFakeArchive(t, "1.500.0")
+	require.NoError(t, extract(archive, cache.dir("v1.500.0")))
+	require.NoError(t, cache.store("v1.500.0", archive, "speakeasy_linux_amd64.zip"))
+
+	binary, ok := cache.lookup("v1.500.0")
+	require.True(t, ok)
+	assert.Equal(t, cache.binary("v1.500.0"), binary)
+
+	cachedArchive, ok := cache.archive("v1.500.0", "speakeasy_linux_amd64.zip")
+	require.True(t, ok)
+	assert.FileExists(t, cachedArchive)
+
+	// A binary changed since it was cached is removed
+	require.NoError(t, os.WriteFile(cache.binary("v1.500.0"), []byte("tampered"), 0o755))
+	_, ok = cache.lookup("v1.500.0")
+	assert.False(t, ok)
+	assert.NoDirExists(t, cache.dir("v1.500.0"))
+
+	// A binary without a recorded digest is removed
+	writeFakeCLI(t, cache.binary("v1.450.0"), "1.450.0")
+	_, ok = cache.lookup("v1.450.0")
+	assert.False(t, ok)
+	assert.NoDirExists(t, cache.dir("v1.450.0"))
+}
+
+// writeFakeArchive returns the path of a release archive of a fake cli.
+func writeFakeArchive(t *testing.T, version string) string {
+	t.Helper()
+
+	archive := filepath.Join(t.TempDir(), "speakeasy_linux_amd64.zip")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	defer f.Close()
+
+	z := zip.NewWriter(f)
+	w, err := z.Create("speakeasy")
+	require.NoError(t, err)
+	_, err = fmt.Fprintf(w, "#!/bin/sh\necho 'speakeasy version %s'\n", version)
+	require.NoError(t, err)
+	require.NoError(t, z.Close())
+
+	return archive
+}
+
+type fakeRelease struct {
+	checksumsLink string
+}
+
+func (f fakeRelease) GetDownloadLink(version string) (string, string, error) {
+	return "", version, nil
+}
+
+func (f fakeRelease) GetChecksumsLinks(tag string) (string, string, error) {
+	return f.checksumsLink, "", nil
+}
+
+func TestUseCachedRelease(t *testing.T) {
+	t.Setenv("INPUT_CLI_CACHE_DIR", t.TempDir())
+	t.Setenv("SPEAKEASY_CLI_SHA256", "")
+	cache := newToolCache()
+	require.NotNil(t, cache)
+
+	archive := writeFakeArchive(t, "1.500.0")
+	digest, err := download.SHA256File(archive)
+	require.NoError(t, err)
+
+	published := digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  speakeasy_linux_amd64.zip\n", published)
+	}))
+	defer server.Close()
+
+	r := fakeRelease{checksumsLink: server.URL + "/checksums.txt"}
+	link := "https://example.com/speakeasy_linux_amd64.zip"
+	binary := filepath.Join(t.TempDir(), "bin", "speakeasy")
+
+	require.NoError(t, extract(archive, cache.dir("v1.500.0")))
+	require.NoError(t, cache.store("v1.500.0", archive, "speakeasy_linux_amd64.zip"))
+
+	// A poisoned binary, recorded alongside it, is replaced by the verified
+	// archive before being used
+	require.NoError(t, os.WriteFile(cache.binary("v1.500.0"), []byte("poisoned"), 0o755))
+	require.NoError(t, cache.store("v1.500.0", archive, "speakeasy_linux_amd64.zip"))
+
+	ok, err := useCachedRelease(cache, r, "v1.500.0", link, binary)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, digest, os.Getenv("SPEAKEASY_CLI_SHA256"))
+
+	data, err := os.ReadFile(binary)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho 'speakeasy version 1.500.0'\n", string(data))
+
+	// An archive that doesn't match the published checksum is removed
+	published = strings.Repeat("0", 64)
+	ok, err = useCachedRelease(cache, r, "v1.500.0", link, binary)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoDirExists(t, cache.dir("v1.500.0"))
+
+	// Without verification the recorded digest of the binary is enough
+	t.Setenv("INPUT_VERIFY_CLI", "false")
+	writeFakeCLI(t, cache.binary("v1.450.0"), "1.450.0")
+	require.NoError(t, cache.store("v1.450.0", archive, "speakeasy_linux_amd64.zip"))
+	ok, err = useCachedRelease(cache, r, "v1.450.0", link, binary)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestToolCache_Prune(t *testing.T) {
+	t.Setenv("INPUT_CLI_CACHE_DIR", t.TempDir())
+	cache := newToolCache()
+	require.NotNil(t, cache)
+
+	now := time.Now()
+	for i := 0; i < maxCachedVersions+2; i++ {
+		version := fmt.Sprintf("v1.%d.0", i)
+		require.NoError(t, os.MkdirAll(cache.dir(version), os.ModePerm))
+		usedAt := now.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(cache.root, version), usedAt, usedAt))
+	}
+
+	// The current version is kept even if it is the least recently used
+	require.NoError(t, cache.prune("v1.0.0"))
+
+	entries, err := os.ReadDir(cache.root)
+	require.NoError(t, err)
+
+	var versions []string
+	for _, entry := range entries {
+		versions = append(versions, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"v1.0.0", "v1.3.0", "v1.4.0", "v1.5.0", "v1.6.0"}, versions)
+}
+
+func TestNewToolCache_RunnerToolCache(t *testing.T) {
+	t.Setenv("INPUT_CLI_CACHE_DIR", "")
+	t.Setenv("RUNNER_TOOL_CACHE", "/opt/hostedtoolcache")
+
+	cache := newToolCache()
+	require.NotNil(t, cache)
+	assert.Equal(t, filepath.Join("/opt/hostedtoolcache", "speakeasy", "v1.500.0", runtime.GOOS+"-"+runtime.GOARCH), cache.dir("v1.500.0"))
+
+	t.Setenv("RUNNER_TOOL_CACHE", "")
+	assert.Nil(t, newToolCache())
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+)
+
+// Feature is a capability of the speakeasy cli the action depends on.
+type Feature string
+
+const (
+	// FeaturePRDescription is the `ci pr-description` command.
+	FeaturePRDescription Feature = "pr-description"
+	// FeatureVersionReports is the capture of versioning reports during `run`.
+	FeatureVersionReports Feature = "version-reports"
+	// FeatureSkipTesting is the --skip-testing flag of `run`.
+	FeatureSkipTesting Feature = "skip-testing"
+	// FeatureRepoSubdirs is the --repo-subdirs flag of `run`.
+	FeatureRepoSubdirs Feature = "repo-subdirs"
+	// FeatureSuggestions is the `suggest` command used by the suggestion actions.
+	FeatureSuggestions Feature = "suggestions"
+)
+
+// Capabilities describes what the installed speakeasy cli supports.
+type Capabilities struct {
+	Version *version.Version
+	// GenerationVersion is only reported by the capabilities command.
+	GenerationVersion *version.Version
+	Features          map[Feature]bool
+	SupportedTargets  []string
+}
+
+// Has reports whether the cli supports the feature.
+func (c *Capabilities) Has(feature Feature) bool {
+	return c.Features[feature]
+}
+
+// capabilitiesOutput is the output of `speakeasy capabilities --output json`.
+type capabilitiesOutput struct {
+	Version           string    `json:"version"`
+	GenerationVersion string    `json:"generation_version"`
+	Features          []Feature `json:"features"`
+	Targets           []string  `json:"targets"`
+}
+
+// fallback detects a feature of clis predating the capabilities command, from
+// the version the feature was introduced in or by probing the command
+// providing it.
+type fallback struct {
+	minVersion *version.Version
+	probe      []string
+	// flag must be listed in the probe output, if set
+	flag string
+}
+
+var fallbacks = map[Feature]fallback{
+	FeaturePRDescription:  {probe: []string{"ci", "pr-description", "--help"}},
+	FeatureVersionReports: {probe: []string{"run", "--help"}, flag: "--skip-versioning"},
+	FeatureSkipTesting:    {probe: []string{"run", "--help"}, flag: "--skip-testing"},
+	FeatureRepoSubdirs:    {probe: []string{"run", "--help"}, flag: "--repo-subdirs"},
+	FeatureSuggestions:    {minVersion: MinimumSupportedCLIVersion},
+}
+
+var (
+	capabilitiesMu sync.Mutex
+	capabilities   *Capabilities
+)
+
+// GetCapabilities returns the capabilities of the installed speakeasy cli,
+// querying it on first use. Capabilities are only cached once the cli could be
+// queried, as it may not be installed yet.
+func GetCapabilities() *Capabilities {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	if capabilities != nil {
+		return capabilities
+	}
+
+	caps, ok := detectCapabilities(probeSpeakeasyCommand)
+	if !ok {
+		logging.Debug("Speakeasy cli could not be queried for its capabilities")
+		return caps
+	}
+
+	var features []string
+	for feature, ok := range caps.Features {
+		if ok {
+			features = append(features, string(feature))
+		}
+	}
+	slices.Sort(features)
+	logging.Debug("Speakeasy cli capabilities: %s", strings.Join(features, ", "))
+
+	capabilities = caps
+	return capabilities
+}
+
+// resetCapabilities clears the cached capabilities, for a newly installed cli.
+func resetCapabilities() {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	capabilities = nil
+}
+
+// detectCapabilities queries the cli with run, reporting whether the cli could
+// be run at all.
+func detectCapabilities(run func(args ...string) (string, error)) (*Capabilities, bool) {
+	if out, err := run("capabilities", "--output", "json"); err == nil {
+		caps, err := parseCapabilities(out)
+		if err == nil {
+			return caps, true
+		}
+		logging.Debug("Failed to parse speakeasy cli capabilities: %v", err)
+	}
+
+	caps := &Capabilities{
+		Features:         map[Feature]bool{},
+		SupportedTargets: detectSupportedTargets(run),
+	}
+
+	out, err := run("--version")
+	if err != nil {
+		return caps, false
+	}
+	if v := binaryVersionRegex.FindString(out); v != "" {
+		caps.Version, _ = version.NewVersion(v)
+	}
+
+	type probeResult struct {
+		out string
+		ok  bool
+	}
+
+	// Probes are shared between the flags of a command
+	probes := map[string]probeResult{}
+	for feature, fb := range fallbacks {
+		switch {
+		case fb.minVersion != nil:
+			caps.Features[feature] = caps.Version != nil && caps.Version.GreaterThanOrEqual(fb.minVersion)
+		case len(fb.probe) > 0:
+			key := strings.Join(fb.probe, " ")
+			res, ok := probes[key]
+			if !ok {
+				out, err := run(fb.probe...)
+				res = probeResult{out: out, ok: err == nil}
+				probes[key] = res
+			}
+
+			caps.Features[feature] = res.ok && strings.Contains(res.out, fb.flag)
+		}
+	}
+
+	return caps, true
+}
+
+func parseCapabilities(out string) (*Capabilities, error) {
+	var output capabilitiesOutput
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		return nil, err
+	}
+
+	v, err := version.NewVersion(output.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse speakeasy version %s: %w", output.Version, err)
+	}
+
+	caps := &Capabilities{
+		Version:          v,
+		Features:         map[Feature]bool{},
+		SupportedTargets: output.Targets,
+	}
+	for _, feature := range output.Features {
+		caps.Features[feature] = true
+	}
+
+	if output.GenerationVersion != "" {
+		caps.GenerationVersion, err = version.NewVersion(output.GenerationVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse generation version %s: %w", output.GenerationVersion, err)
+		}
+	}
+
+	if len(caps.SupportedTargets) == 0 {
+		caps.SupportedTargets = defaultSupportedTargets
+	}
+
+	return caps, nil
+}
+
+func detectSupportedTargets(run func(args ...string) (string, error)) []string {
+	out, err := run("generate", "supported-targets")
+	if err == nil && out != "" {
+		out = strings.Trim(out, "\n")
+		out = strings.Trim(out, " ")
+		supportedTargets := strings.Split(out, ",")
+		// quick sanity check
+		if len(supportedTargets) > 0 && slices.Contains(supportedTargets, "go") {
+			return supportedTargets
+		}
+	}
+
+	return defaultSupportedTargets
+}
+
+// probeSpeakeasyCommand runs a speakeasy command without streaming its output,
+// for commands that are only run to inspect the cli.
+func probeSpeakeasyCommand(args ...string) (string, error) {
+	cmd := process.Command(process.Context(), process.PhaseProbe, filepath.Join(environment.GetBaseDir(), "bin", "speakeasy"), args...)
+	cmd.Dir = environment.GetRepoPath()
+	cmd.Env = append(os.Environ(), "SPEAKEASY_RUN_LOCATION=action", "SPEAKEASY_ENVIRONMENT=github")
+
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeCLI(outputs map[string]string) func(args ...string) (string, error) {
+	return func(args ...string) (string, error) {
+		out, ok := outputs[strings.Join(args, " ")]
+		if !ok {
+			return "", errors.New("unknown command")
+		}
+
+		return out, nil
+	}
+}
+
+func TestDetectCapabilities_CapabilitiesCommand(t *testing.T) {
+	caps, ok := detectCapabilities(fakeCLI(map[string]string{
+		"capabilities --output json": `{"version": "1.600.0", "generation_version": "2.700.1", "features": ["pr-description", "skip-testing"], "targets": ["go", "typescript"]}`,
+	}))
+
+	assert.True(t, ok)
+	assert.Equal(t, "1.600.0", caps.Version.String())
+	assert.Equal(t, "2.700.1", caps.GenerationVersion.String())
+	assert.True(t, caps.Has(FeaturePRDescription))
+	assert.True(t, caps.Has(FeatureSkipTesting))
+	assert.False(t, caps.Has(FeatureRepoSubdirs))
+	assert.Equal(t, []string{"go", "typescript"}, caps.SupportedTargets)
+}
+
+func TestDetectCapabilities_Fallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		outputs     map[string]string
+		want        []Feature
+		wantTargets []string
+		wantOK      bool
+	}{
+		{
+			name: "recent cli",
+			outputs: map[string]string{
+				"--version":                  "speakeasy version 1.500.0\n",
+				"ci pr-description --help":   "Generate a PR description",
+				"run --help":                 "--repo-subdirs string\n--skip-testing\n--skip-versioning",
+				"generate supported-targets": "go,python,typescript\n",
+			},
+			want:        []Feature{FeaturePRDescription, FeatureVersionReports, FeatureSkipTesting, FeatureRepoSubdirs, FeatureSuggestions},
+			wantTargets: []string{"go", "python", "typescript"},
+			wantOK:      true,
+		},
+		{
+			name: "old cli",
+			outputs: map[string]string{
+				"--version":  "speakeasy version 1.100.0\n",
+				"run --help": "--skip-testing",
+			},
+			want:        []Feature{FeatureSkipTesting},
+			wantTargets: defaultSupportedTargets,
+			wantOK:      true,
+		},
+		{
+			name:        "missing cli",
+			outputs:     map[string]string{},
+			wantTargets: defaultSupportedTargets,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps, ok := detectCapabilities(fakeCLI(tt.outputs))
+			assert.Equal(t, tt.wantOK, ok)
+
+			for feature := range fallbacks {
+				assert.Equal(t, slices.Contains(tt.want, feature), caps.Has(feature), feature)
+			}
+			assert.Equal(t, tt.wantTargets, caps.SupportedTargets)
+		})
+	}
+}
+
+func TestDetectCapabilities_InvalidCapabilitiesOutput(t *testing.T) {
+	caps, ok := detectCapabilities(fakeCLI(map[string]string{
+		"capabilities --output json": "not json",
+		"--version":                  "speakeasy version 1.500.0",
+	}))
+
+	assert.True(t, ok)
+	require.NotNil(t, caps.Version)
+	assert.Equal(t, "1.500.0", caps.Version.String())
+	assert.True(t, caps.Has(FeatureSuggestions))
+	assert.False(t, caps.Has(FeaturePRDescription))
+}
+
+func TestGetCapabilities_NotCachedWithoutCLI(t *testing.T) {
+	resetCapabilities()
+	t.Cleanup(resetCapabilities)
+
+	// No cli is installed in tests, so its capabilities must be queried again
+	// once it is
+	caps := GetCapabilities()
+	require.NotNil(t, caps)
+	assert.Nil(t, caps.Version)
+	assert.Nil(t, capabilities)
+}
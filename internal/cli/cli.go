@@ -1,14 +1,14 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"regexp"
 	"strings"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
-	"golang.org/x/exp/slices"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
 
 	"github.com/hashicorp/go-version"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
@@ -16,16 +16,6 @@ import (
 
 var MinimumSupportedCLIVersion = version.Must(version.NewVersion("1.130.0"))
 
-func IsAtLeastVersion(version *version.Version) bool {
-	sv, err := GetSpeakeasyVersion()
-	if err != nil {
-		logging.Debug("%s", err.Error())
-		return false
-	}
-
-	return sv.GreaterThanOrEqual(version)
-}
-
 var defaultSupportedTargets = []string{
 	"cli",
 	"csharp",
@@ -36,6 +26,7 @@ var defaultSupportedTargets = []string{
 	"postman",
 	"python",
 	"ruby",
+	"swift",
 	"terraform",
 	"typescript",
 }
@@ -52,29 +43,14 @@ var DefaultSupportedTargetsForChangelog = []string{
 	"ruby",
 }
 
-func GetSupportedTargetNames() []string {
-	out, err := runSpeakeasyCommand("generate", "supported-targets")
-	if err == nil && out != "" {
-		out = strings.Trim(out, "\n")
-		out = strings.Trim(out, " ")
-		supportedTargets := strings.Split(out, ",")
-		// quick sanity check
-		if len(supportedTargets) > 0 && slices.Contains(supportedTargets, "go") {
-			return supportedTargets
-		}
-	}
-
-	return defaultSupportedTargets
-}
-
-func TriggerGoGenerate() error {
-	tidyCmd := exec.Command("go", "mod", "tidy")
+func TriggerGoGenerate(ctx context.Context) error {
+	tidyCmd := process.Command(ctx, process.PhaseGenerate, "go", "mod", "tidy")
 	tidyCmd.Dir = environment.GetRepoPath()
 	output, err := tidyCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error running command: go mod tidy - %w\n %s", err, string(output))
 	}
-	generateCmd := exec.Command("go", "generate", "./...")
+	generateCmd := process.Command(ctx, process.PhaseGenerate, "go", "generate", "./...")
 	generateCmd.Dir = environment.GetRepoPath()
 	output, err = generateCmd.CombinedOutput()
 	if err != nil {
@@ -84,8 +60,12 @@ func TriggerGoGenerate() error {
 	return nil
 }
 
-func GetSpeakeasyVersion() (*version.Version, error) {
-	out, err := runSpeakeasyCommand("--version")
+func GetSpeakeasyVersion(ctx context.Context) (*version.Version, error) {
+	if v := GetCapabilities().Version; v != nil {
+		return v, nil
+	}
+
+	out, err := runSpeakeasyCommand(ctx, "--version")
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +74,11 @@ func GetSpeakeasyVersion() (*version.Version, error) {
 
 	r := regexp.MustCompile(`speakeasy version ([0-9]+\.[0-9]+\.[0-9]+)`)
 
-	v := r.FindStringSubmatch(strings.TrimSpace(out))[1]
+	matches := r.FindStringSubmatch(strings.TrimSpace(out))
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("failed to parse speakeasy version from %q", strings.TrimSpace(out))
+	}
+	v := matches[1]
 
 	ver, err := version.NewVersion(v)
 	if err != nil {
@@ -104,8 +88,12 @@ func GetSpeakeasyVersion() (*version.Version, error) {
 	return ver, nil
 }
 
-func GetGenerationVersion() (*version.Version, error) {
-	out, err := runSpeakeasyCommand("generate", "sdk", "version")
+func GetGenerationVersion(ctx context.Context) (*version.Version, error) {
+	if v := GetCapabilities().GenerationVersion; v != nil {
+		return v, nil
+	}
+
+	out, err := runSpeakeasyCommand(ctx, "generate", "sdk", "version")
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +102,11 @@ func GetGenerationVersion() (*version.Version, error) {
 
 	r := regexp.MustCompile(`(?m)^Version:.*?v([0-9]+\.[0-9]+\.[0-9]+)`)
 
-	v := r.FindStringSubmatch(strings.TrimSpace(out))[1]
+	matches := r.FindStringSubmatch(strings.TrimSpace(out))
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("failed to parse generation version from %q", strings.TrimSpace(out))
+	}
+	v := matches[1]
 
 	genVersion, err := version.NewVersion(v)
 	if err != nil {
@@ -124,7 +116,7 @@ func GetGenerationVersion() (*version.Version, error) {
 	return genVersion, nil
 }
 
-func GetChangelog(lang, genVersion, previousGenVersion string, targetVersions map[string]string, previousVersions map[string]string) (string, error) {
+func GetChangelog(ctx context.Context, lang, genVersion, previousGenVersion string, targetVersions map[string]string, previousVersions map[string]string) (string, error) {
 	targetVersionsStrings := []string{}
 
 	for feature, targetVersion := range targetVersions {
@@ -152,7 +144,7 @@ func GetChangelog(lang, genVersion, previousGenVersion string, targetVersions ma
 		args = append(args, "-p", strings.Join(previosVersionsStrings, ","))
 	}
 
-	out, err := runSpeakeasyCommand(args...)
+	out, err := runSpeakeasyCommand(ctx, args...)
 	if err != nil {
 		return "", err
 	}
@@ -160,8 +152,8 @@ func GetChangelog(lang, genVersion, previousGenVersion string, targetVersions ma
 	return out, nil
 }
 
-func Suggest(docPath, maxSuggestions, docOutputPath string) (string, error) {
-	out, err := runSpeakeasyCommand("suggest", "--schema", docPath, "--auto-approve", "--output-file", docOutputPath, "--max-suggestions", maxSuggestions, "--level", "hint", "--serial")
+func Suggest(ctx context.Context, docPath, maxSuggestions, docOutputPath string) (string, error) {
+	out, err := runSpeakeasyCommand(ctx, "suggest", "--schema", docPath, "--auto-approve", "--output-file", docOutputPath, "--max-suggestions", maxSuggestions, "--level", "hint", "--serial")
 	if err != nil {
 		return out, fmt.Errorf("error suggesting openapi fixes: %w", err)
 	}
@@ -169,7 +161,7 @@ func Suggest(docPath, maxSuggestions, docOutputPath string) (string, error) {
 	return out, nil
 }
 
-func MergeDocuments(files []string, output string) error {
+func MergeDocuments(ctx context.Context, files []string, output string) error {
 	args := []string{
 		"merge",
 		"-o",
@@ -180,14 +172,14 @@ func MergeDocuments(files []string, output string) error {
 		args = append(args, "-s", f)
 	}
 
-	_, err := runSpeakeasyCommand(args...)
+	_, err := runSpeakeasyCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("error merging documents: %w", err)
 	}
 	return nil
 }
 
-func ApplyOverlay(overlayPath, inPath, outPath string) error {
+func ApplyOverlay(ctx context.Context, overlayPath, inPath, outPath string) error {
 	args := []string{
 		"overlay",
 		"apply",
@@ -197,7 +189,7 @@ func ApplyOverlay(overlayPath, inPath, outPath string) error {
 		inPath,
 	}
 
-	out, err := runSpeakeasyCommand(args...)
+	out, err := runSpeakeasyCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("error applying overlay: %w", err)
 	}
@@ -2,14 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
 	"github.com/speakeasy-api/versioning-reports/versioning"
 )
 
@@ -46,37 +47,10 @@ type PRDescriptionOutput struct {
 	Body  string `json:"body"`
 }
 
-// supportsPRDescriptionCommand checks if the CLI supports the `ci pr-description` command.
-// Returns false if the command is not available (older CLI version).
-var supportsPRDescriptionCommand *bool
-
-func SupportsPRDescriptionCommand() bool {
-	if supportsPRDescriptionCommand != nil {
-		return *supportsPRDescriptionCommand
-	}
-
-	baseDir := environment.GetBaseDir()
-	cmdPath := filepath.Join(baseDir, "bin", "speakeasy")
-
-	cmd := exec.Command(cmdPath, "ci", "pr-description", "--help")
-	err := cmd.Run()
-
-	result := err == nil
-	supportsPRDescriptionCommand = &result
-
-	if result {
-		logging.Debug("CLI supports ci pr-description command")
-	} else {
-		logging.Debug("CLI does not support ci pr-description command, will use legacy fallback")
-	}
-
-	return result
-}
-
 // GeneratePRDescription calls the CLI to generate a PR title and body.
 // Returns nil, nil if the CLI doesn't support this command (caller should use fallback).
-func GeneratePRDescription(input PRDescriptionInput) (*PRDescriptionOutput, error) {
-	if !SupportsPRDescriptionCommand() {
+func GeneratePRDescription(ctx context.Context, input PRDescriptionInput) (*PRDescriptionOutput, error) {
+	if !GetCapabilities().Has(FeaturePRDescription) {
 		return nil, nil
 	}
 
@@ -92,7 +66,7 @@ func GeneratePRDescription(input PRDescriptionInput) (*PRDescriptionOutput, erro
 	logging.Info("Generating PR description via CLI")
 	logging.Debug("PR description input: %s", string(inputJSON))
 
-	cmd := exec.Command(cmdPath, "ci", "pr-description", "--input", "-")
+	cmd := process.Command(ctx, process.PhaseGenerate, cmdPath, "ci", "pr-description", "--input", "-")
 	cmd.Dir = environment.GetRepoPath()
 	cmd.Stdin = bytes.NewReader(inputJSON)
 	cmd.Env = os.Environ()
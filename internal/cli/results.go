@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RunResultsFileEnvVar is read by the CLI for the path it writes the results of
+// `speakeasy run` to.
+const RunResultsFileEnvVar = "SPEAKEASY_RUN_RESULTS_FILE"
+
+type TargetStatus string
+
+const (
+	TargetStatusGenerated TargetStatus = "generated"
+	TargetStatusUnchanged TargetStatus = "unchanged"
+	TargetStatusSkipped   TargetStatus = "skipped"
+	TargetStatusFailed    TargetStatus = "failed"
+)
+
+// TargetResult is the outcome of generating a single target.
+type TargetResult struct {
+	Status   TargetStatus
+	Duration time.Duration
+	Warnings []string
+	Error    string
+}
+
+// runResultsFile is the JSON contract of the results file written by the CLI.
+type runResultsFile struct {
+	LintingReportURL     string                      `json:"linting_report_url"`
+	ChangesReportURL     string                      `json:"changes_report_url"`
+	OpenAPIChangeSummary string                      `json:"openapi_change_summary"`
+	DurationMs           int64                       `json:"duration_ms"`
+	Warnings             []string                    `json:"warnings"`
+	Targets              map[string]targetResultFile `json:"targets"`
+}
+
+type targetResultFile struct {
+	Status     TargetStatus `json:"status"`
+	DurationMs int64        `json:"duration_ms"`
+	Warnings   []string     `json:"warnings"`
+	Error      string       `json:"error"`
+}
+
+// readRunResults decodes the results file written by the CLI, returning nil if
+// the CLI didn't write one, as older CLIs don't.
+func readRunResults(path string) (*RunResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading run results: %w", err)
+	}
+
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, nil
+	}
+
+	var file runResultsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing run results: %w", err)
+	}
+
+	results := &RunResults{
+		LintingReportURL:     file.LintingReportURL,
+		ChangesReportURL:     file.ChangesReportURL,
+		OpenAPIChangeSummary: file.OpenAPIChangeSummary,
+		Duration:             time.Duration(file.DurationMs) * time.Millisecond,
+		Warnings:             file.Warnings,
+		Targets:              map[string]TargetResult{},
+	}
+
+	for targetID, target := range file.Targets {
+		results.Targets[targetID] = TargetResult{
+			Status:   target.Status,
+			Duration: time.Duration(target.DurationMs) * time.Millisecond,
+			Warnings: target.Warnings,
+			Error:    target.Error,
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRunResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "linting_report_url": "https://app.speakeasy.com/org/test/test/linting-report/abc",
+  "changes_report_url": "https://app.speakeasy.com/org/test/test/changes-report/def",
+  "openapi_change_summary": "Added GET /pets",
+  "duration_ms": 5000,
+  "warnings": ["deprecated option"],
+  "targets": {
+    "my-typescript": {"status": "generated", "duration_ms": 3000, "warnings": ["unused schema"]},
+    "my-python": {"status": "failed", "duration_ms": 1000, "error": "compilation failed"}
+  }
+}`), 0o600))
+
+	results, err := readRunResults(path)
+	require.NoError(t, err)
+	require.NotNil(t, results)
+
+	assert.Equal(t, &RunResults{
+		LintingReportURL:     "https://app.speakeasy.com/org/test/test/linting-report/abc",
+		ChangesReportURL:     "https://app.speakeasy.com/org/test/test/changes-report/def",
+		OpenAPIChangeSummary: "Added GET /pets",
+		Duration:             5 * time.Second,
+		Warnings:             []string{"deprecated option"},
+		Targets: map[string]TargetResult{
+			"my-typescript": {Status: TargetStatusGenerated, Duration: 3 * time.Second, Warnings: []string{"unused schema"}},
+			"my-python":     {Status: TargetStatusFailed, Duration: time.Second, Error: "compilation failed"},
+		},
+	}, results)
+}
+
+func TestReadRunResults_NotWritten(t *testing.T) {
+	dir := t.TempDir()
+
+	results, err := readRunResults(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Nil(t, results)
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, nil, 0o600))
+
+	results, err = readRunResults(empty)
+	require.NoError(t, err)
+	assert.Nil(t, results)
+}
+
+func TestReadRunResults_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	_, err := readRunResults(path)
+	assert.Error(t, err)
+}
+
+func TestFillRunResults(t *testing.T) {
+	changeSummary := filepath.Join(t.TempDir(), "change-summary")
+	require.NoError(t, os.WriteFile(changeSummary, []byte("Added GET /pets"), 0o600))
+
+	out := "Linting report: https://app.speakeasy.com/org/test/test/linting-report/abc \n" +
+		"Changes report: https://app.speakeasy.com/org/test/test/changes-report/def \n"
+
+	// Each field missing from the results file falls back on its own
+	results := fillRunResults(&RunResults{
+		ChangesReportURL: "https://app.speakeasy.com/org/test/test/changes-report/xyz",
+		Targets:          map[string]TargetResult{"my-python": {Status: TargetStatusFailed, Error: "compilation failed"}},
+	}, out, changeSummary)
+
+	assert.Equal(t, &RunResults{
+		LintingReportURL:     "https://app.speakeasy.com/org/test/test/linting-report/abc",
+		ChangesReportURL:     "https://app.speakeasy.com/org/test/test/changes-report/xyz",
+		OpenAPIChangeSummary: "Added GET /pets",
+		Targets:              map[string]TargetResult{"my-python": {Status: TargetStatusFailed, Error: "compilation failed"}},
+	}, results)
+
+	// Without a results file, everything comes from the fallbacks
+	results = fillRunResults(nil, out, filepath.Join(t.TempDir(), "missing"))
+	assert.Equal(t, &RunResults{
+		LintingReportURL: "https://app.speakeasy.com/org/test/test/linting-report/abc",
+		ChangesReportURL: "https://app.speakeasy.com/org/test/test/changes-report/def",
+	}, results)
+}
@@ -1,13 +1,16 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	"github.com/speakeasy-api/sdk-generation-action/internal/registry"
 	"github.com/speakeasy-api/versioning-reports/versioning"
 )
@@ -18,9 +21,14 @@ type RunResults struct {
 	LintingReportURL     string
 	ChangesReportURL     string
 	OpenAPIChangeSummary string
+	// Targets maps target IDs to their results. It is only populated by CLIs
+	// writing a results file.
+	Targets  map[string]TargetResult
+	Duration time.Duration
+	Warnings []string
 }
 
-func Run(sourcesOnly bool, installationURLs map[string]string, repoURL string, repoSubdirectories map[string]string, manualVersionBump *versioning.BumpType) (*RunResults, error) {
+func Run(ctx context.Context, sourcesOnly bool, installationURLs map[string]string, repoURL string, repoSubdirectories map[string]string, manualVersionBump *versioning.BumpType) (*RunResults, error) {
 	args := []string{
 		"run",
 	}
@@ -44,7 +52,11 @@ func Run(sourcesOnly bool, installationURLs map[string]string, repoURL string, r
 		if err != nil {
 			return nil, fmt.Errorf("error marshalling repo subdirectories: %w", err)
 		}
-		args = append(args, "--repo-subdirs", string(subdirs))
+		if GetCapabilities().Has(FeatureRepoSubdirs) {
+			args = append(args, "--repo-subdirs", string(subdirs))
+		} else {
+			logging.Info("Speakeasy cli does not support --repo-subdirs, targets in subdirectories may get incorrect repository links")
+		}
 	}
 
 	if repoURL != "" {
@@ -63,7 +75,11 @@ func Run(sourcesOnly bool, installationURLs map[string]string, repoURL string, r
 
 	// If we are in PR mode we skip testing on generation, this should run as a PR check
 	if environment.SkipTesting() || (environment.GetMode() == environment.ModePR && !sourcesOnly) {
-		args = append(args, "--skip-testing")
+		if GetCapabilities().Has(FeatureSkipTesting) {
+			args = append(args, "--skip-testing")
+		} else {
+			logging.Info("Speakeasy cli does not support --skip-testing, tests will run as configured")
+		}
 	}
 
 	if environment.SkipCompile() {
@@ -75,7 +91,7 @@ func Run(sourcesOnly bool, installationURLs map[string]string, repoURL string, r
 	}
 
 	if environment.ForceGeneration() {
-		fmt.Println("\nforce input enabled - setting SPEAKEASY_FORCE_GENERATION=true")
+		logging.Info("force input enabled - setting SPEAKEASY_FORCE_GENERATION=true")
 		os.Setenv("SPEAKEASY_FORCE_GENERATION", "true")
 	}
 
@@ -96,23 +112,64 @@ func Run(sourcesOnly bool, installationURLs map[string]string, repoURL string, r
 		return nil, fmt.Errorf("error closing change summary file: %w", err)
 	}
 
-	out, err := runSpeakeasyCommand(args...)
+	resultsFile, err := os.CreateTemp(os.TempDir(), "speakeasy-run-results")
+	if err != nil {
+		return nil, fmt.Errorf("error creating run results file: %w", err)
+	}
+	os.Setenv(RunResultsFileEnvVar, resultsFile.Name())
+	err = resultsFile.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing run results file: %w", err)
+	}
+	defer os.Remove(resultsFile.Name())
+
+	out, runErr := runSpeakeasyCommand(ctx, args...)
+	annotateValidationFindings(out)
+
+	// Results are read even if the run failed, to report the targets that did
+	results, err := readRunResults(resultsFile.Name())
 	if err != nil {
-		return nil, fmt.Errorf("error running workflow: %w", err)
+		logging.Info("Falling back to parsing the output of speakeasy run: %v", err)
 	}
+	results = fillRunResults(results, out, file.Name())
 
-	lintingReportURL := getLintingReportURL(out)
-	changesReportURL := getChangesReportURL(out)
-	// read from file
-	// ignore errors: the change summary is optional
-	// and won't be available first run
-	changeSummary, _ := os.ReadFile(file.Name())
+	for _, warning := range results.Warnings {
+		logging.Info("Warning: %s", warning)
+	}
+	for targetID, target := range results.Targets {
+		for _, warning := range target.Warnings {
+			logging.Info("Warning (%s): %s", targetID, warning)
+		}
+	}
+
+	if runErr != nil {
+		return results, fmt.Errorf("error running workflow: %w", runErr)
+	}
+
+	return results, nil
+}
+
+// fillRunResults fills the fields missing from the results file, which older
+// CLIs don't write, from the output of the run and the change summary file.
+func fillRunResults(results *RunResults, out, changeSummaryPath string) *RunResults {
+	if results == nil {
+		results = &RunResults{}
+	}
+
+	if results.LintingReportURL == "" {
+		results.LintingReportURL = getLintingReportURL(out)
+	}
+	if results.ChangesReportURL == "" {
+		results.ChangesReportURL = getChangesReportURL(out)
+	}
+	if results.OpenAPIChangeSummary == "" {
+		// ignore errors: the change summary is optional
+		// and won't be available first run
+		changeSummary, _ := os.ReadFile(changeSummaryPath)
+		results.OpenAPIChangeSummary = string(changeSummary)
+	}
 
-	return &RunResults{
-		LintingReportURL:     lintingReportURL,
-		ChangesReportURL:     changesReportURL,
-		OpenAPIChangeSummary: string(changeSummary),
-	}, nil
+	return results
 }
 
 var (
@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+)
+
+var binaryVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+\S*`)
+
+// versionIndex lists the speakeasy cli versions available to a mirrored or
+// offline installation, e.g. {"latest": "v1.500.0", "versions": ["v1.500.0"]}.
+type versionIndex struct {
+	Latest   string   `json:"latest"`
+	Versions []string `json:"versions"`
+}
+
+// resolveVersion resolves the version against the cli_version_index, if one is
+// configured, so that no github.com API calls are needed.
+func resolveVersion(version string) (string, error) {
+	location := environment.GetCLIVersionIndex()
+	if location == "" {
+		return version, nil
+	}
+
+	index, err := loadVersionIndex(location)
+	if err != nil {
+		return version, err
+	}
+
+	return index.resolve(version)
+}
+
+func loadVersionIndex(location string) (*versionIndex, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		data, err = fetch(location)
+	} else {
+		data, err = os.ReadFile(location)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cli version index %s: %w", location, err)
+	}
+
+	var index versionIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse cli version index %s: %w", location, err)
+	}
+
+	return &index, nil
+}
+
+func (i *versionIndex) resolve(version string) (string, error) {
+	if version == "latest" {
+		if i.Latest != "" {
+			return normalizeVersion(i.Latest), nil
+		}
+
+		var latest *goversion.Version
+		for _, v := range i.Versions {
+			parsed, err := goversion.NewVersion(v)
+			if err != nil {
+				continue
+			}
+			if latest == nil || parsed.GreaterThan(latest) {
+				latest = parsed
+			}
+		}
+
+		if latest == nil {
+			return version, fmt.Errorf("no speakeasy cli versions listed in the cli version index")
+		}
+
+		return normalizeVersion(latest.Original()), nil
+	}
+
+	for _, v := range append([]string{i.Latest}, i.Versions...) {
+		if normalizeVersion(v) == version {
+			return version, nil
+		}
+	}
+
+	return version, fmt.Errorf("speakeasy cli version %s is not listed in the cli version index", version)
+}
+
+func normalizeVersion(version string) string {
+	return "v" + strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// mirror serves speakeasy cli release assets from a URL template, such as
+// https://mirror.example.com/speakeasy/{version}/{asset}.
+type mirror struct {
+	urlTemplate string
+}
+
+func newMirror(urlTemplate string) (*mirror, error) {
+	if urlTemplate == "" {
+		return nil, fmt.Errorf("cli_mirror_url is required when cli_source is mirror")
+	}
+
+	if !strings.Contains(urlTemplate, "{asset}") {
+		return nil, fmt.Errorf("cli_mirror_url must contain an {asset} placeholder")
+	}
+
+	return &mirror{urlTemplate: urlTemplate}, nil
+}
+
+func (m *mirror) link(tag, asset string) string {
+	return strings.NewReplacer(
+		"{version}", tag,
+		"{os}", runtime.GOOS,
+		"{arch}", runtime.GOARCH,
+		"{asset}", asset,
+	).Replace(m.urlTemplate)
+}
+
+func (m *mirror) GetDownloadLink(version string) (string, string, error) {
+	if version == "latest" {
+		return "", "", fmt.Errorf("cli_version_index is required to resolve the latest speakeasy cli version from a mirror")
+	}
+
+	return m.link(version, fmt.Sprintf("speakeasy_%s_%s.zip", runtime.GOOS, runtime.GOARCH)), version, nil
+}
+
+func (m *mirror) GetChecksumsLinks(tag string) (string, string, error) {
+	return m.link(tag, "checksums.txt"), m.link(tag, "checksums.txt.sig"), nil
+}
+
+// installFile installs a provisioned speakeasy cli binary or release archive.
+// Provisioned files aren't verified, as no checksums are published with them.
+func installFile(version, file string) (string, error) {
+	if file == "" {
+		return version, fmt.Errorf("cli_file is required when cli_source is file")
+	}
+
+	binDir := filepath.Join(environment.GetBaseDir(), "bin")
+	binary := filepath.Join(binDir, "speakeasy")
+
+	switch filepath.Ext(file) {
+	case ".zip", ".gz":
+		if err := extract(file, binDir); err != nil {
+			return version, fmt.Errorf("failed to extract speakeasy cli: %w", err)
+		}
+	default:
+		if err := copyFile(file, binary); err != nil {
+			return version, fmt.Errorf("failed to install speakeasy cli: %w", err)
+		}
+	}
+
+	if err := os.Chmod(binary, 0o755); err != nil {
+		return version, fmt.Errorf("failed to set permissions on speakeasy cli: %w", err)
+	}
+
+	logging.Info("Installed speakeasy cli from %s", file)
+
+	return checkInstalledVersion(version, binary)
+}
+
+// installFromPath links the speakeasy cli found on the PATH into place.
+func installFromPath(version string) (string, error) {
+	found, err := exec.LookPath("speakeasy")
+	if err != nil {
+		return version, fmt.Errorf("speakeasy cli not found on PATH: %w", err)
+	}
+
+	found, err = filepath.Abs(found)
+	if err != nil {
+		return version, err
+	}
+
+	binary := filepath.Join(environment.GetBaseDir(), "bin", "speakeasy")
+	if err := linkBinary(found, binary); err != nil {
+		return version, err
+	}
+
+	logging.Info("Using speakeasy cli from %s", found)
+
+	return checkInstalledVersion(version, binary)
+}
+
+// linkBinary links the binary into place, replacing whatever was there.
+func linkBinary(src, binary string) error {
+	if src == binary {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(binary), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.Remove(binary); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace speakeasy cli: %w", err)
+	}
+
+	if err := os.Symlink(src, binary); err != nil {
+		return fmt.Errorf("failed to link speakeasy cli: %w", err)
+	}
+
+	return nil
+}
+
+// checkInstalledVersion returns the version of the installed binary, failing
+// if a specific version is required and the binary is a different one.
+func checkInstalledVersion(version, binary string) (string, error) {
+	installed, err := binaryVersion(binary)
+	if err != nil {
+		return version, err
+	}
+
+	if version != "latest" && installed != version {
+		return installed, fmt.Errorf("speakeasy cli %s is installed, but %s is required", installed, version)
+	}
+
+	return installed, nil
+}
+
+// binaryVersion returns the version reported by the speakeasy cli binary.
+func binaryVersion(binary string) (string, error) {
+	out, err := process.Command(process.Context(), process.PhaseSetup, binary, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get speakeasy cli version: %w", err)
+	}
+
+	version := binaryVersionRegex.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("failed to parse speakeasy cli version from %q", strings.TrimSpace(string(out)))
+	}
+
+	return normalizeVersion(version), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
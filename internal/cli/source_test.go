@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionIndex_Resolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   versionIndex
+		version string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "latest from index",
+			index:   versionIndex{Latest: "1.500.0", Versions: []string{"v1.500.0", "v1.499.0"}},
+			version: "latest",
+			want:    "v1.500.0",
+		},
+		{
+			name:    "latest from highest version",
+			index:   versionIndex{Versions: []string{"v1.99.0", "v1.100.0", "invalid"}},
+			version: "latest",
+			want:    "v1.100.0",
+		},
+		{
+			name:    "pinned version listed",
+			index:   versionIndex{Versions: []string{"1.499.0"}},
+			version: "v1.499.0",
+			want:    "v1.499.0",
+		},
+		{
+			name:    "pinned version not listed",
+			index:   versionIndex{Versions: []string{"v1.499.0"}},
+			version: "v1.400.0",
+			wantErr: true,
+		},
+		{
+			name:    "empty index",
+			version: "latest",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.index.resolve(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMirror(t *testing.T) {
+	_, err := newMirror("")
+	assert.Error(t, err)
+
+	_, err = newMirror("https://mirror.example.com/speakeasy/{version}")
+	assert.Error(t, err)
+
+	m, err := newMirror("https://mirror.example.com/speakeasy/{version}/{asset}")
+	require.NoError(t, err)
+
+	link, tag, err := m.GetDownloadLink("v1.500.0")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("https://mirror.example.com/speakeasy/v1.500.0/speakeasy_%s_%s.zip", runtime.GOOS, runtime.GOARCH), link)
+	assert.Equal(t, "v1.500.0", tag)
+
+	checksums, signature, err := m.GetChecksumsLinks("v1.500.0")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirror.example.com/speakeasy/v1.500.0/checksums.txt", checksums)
+	assert.Equal(t, "https://mirror.example.com/speakeasy/v1.500.0/checksums.txt.sig", signature)
+
+	_, _, err = m.GetDownloadLink("latest")
+	assert.Error(t, err)
+}
+
+func TestCheckInstalledVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell")
+	}
+
+	binary := filepath.Join(t.TempDir(), "speakeasy")
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\necho 'speakeasy version 1.500.0'\n"), 0o755))
+
+	got, err := checkInstalledVersion("latest", binary)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.500.0", got)
+
+	got, err = checkInstalledVersion("v1.500.0", binary)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.500.0", got)
+
+	_, err = checkInstalledVersion("v1.400.0", binary)
+	assert.ErrorContains(t, err, "speakeasy cli v1.500.0 is installed, but v1.400.0 is required")
+}
+
+func TestResolveVersion_FromIndexFile(t *testing.T) {
+	index := filepath.Join(t.TempDir(), "index.json")
+	require.NoError(t, os.WriteFile(index, []byte(`{"latest": "v1.500.0", "versions": ["v1.500.0"]}`), 0o600))
+	t.Setenv("INPUT_CLI_VERSION_INDEX", index)
+
+	got, err := resolveVersion("latest")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.500.0", got)
+}
@@ -5,12 +5,12 @@ import (
 	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
@@ -18,11 +18,25 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/download"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 )
 
 type Git interface {
 	GetLatestTag() (string, error)
 	GetDownloadLink(version string) (string, string, error)
+	GetChecksumsLinks(tag string) (string, string, error)
+}
+
+// release is a source of speakeasy cli release assets.
+type release interface {
+	// GetDownloadLink returns the link of the archive of the version for the
+	// current platform, and the tag of the version.
+	GetDownloadLink(version string) (string, string, error)
+	// GetChecksumsLinks returns the links of the checksums of the release with
+	// the tag, and of their signature if published.
+	GetChecksumsLinks(tag string) (string, string, error)
 }
 
 func GetVersion(pinnedVersion string) string {
@@ -41,19 +55,65 @@ func GetVersion(pinnedVersion string) string {
 	return version
 }
 
+// Download installs the speakeasy cli from the configured cli_source, returning
+// the version installed.
 func Download(pinnedVersion string, g Git) (string, error) {
-	version := GetVersion(pinnedVersion)
+	// The capabilities of a previously installed cli may no longer apply
+	defer resetCapabilities()
+
+	version, err := resolveVersion(GetVersion(pinnedVersion))
+	if err != nil {
+		return version, err
+	}
+
+	switch source := environment.GetCLISource(); source {
+	case environment.CLISourceGitHub:
+		return downloadRelease(version, g)
+	case environment.CLISourceMirror:
+		m, err := newMirror(environment.GetCLIMirrorURL())
+		if err != nil {
+			return version, err
+		}
+		return downloadRelease(version, m)
+	case environment.CLISourceFile:
+		return installFile(version, environment.GetCLIFile())
+	case environment.CLISourcePath:
+		return installFromPath(version)
+	default:
+		return version, fmt.Errorf("unsupported cli_source: %s", source)
+	}
+}
 
-	link, version, err := g.GetDownloadLink(version)
+func downloadRelease(version string, r release) (string, error) {
+	link, version, err := r.GetDownloadLink(version)
 	if err != nil {
 		return version, err
 	}
 
-	if _, err := os.Stat(filepath.Join(environment.GetBaseDir(), "bin", "speakeasy")); err == nil {
-		return version, nil
+	binary := filepath.Join(environment.GetBaseDir(), "bin", "speakeasy")
+	// With verify_cli on, an installed binary would run before being verified,
+	// so it is always installed again from a verified archive
+	if !environment.VerifyCLI() {
+		if installed, err := binaryVersion(binary); err == nil && installed == version {
+			return version, nil
+		}
 	}
 
-	fmt.Println("Downloading speakeasy cli version: ", version)
+	installDir := filepath.Dir(binary)
+
+	cache := newToolCache()
+	if cache != nil {
+		if ok, err := useCachedRelease(cache, r, version, link, binary); err != nil || ok {
+			return version, err
+		}
+
+		installDir = cache.dir(version)
+	} else if err := os.Remove(binary); err != nil && !os.IsNotExist(err) {
+		// The binary may link to a cached version, which must not be overwritten
+		return version, fmt.Errorf("failed to replace speakeasy cli: %w", err)
+	}
+
+	logging.With("version", version).Info("Downloading speakeasy cli")
 
 	downloadPath := filepath.Join(os.TempDir(), "speakeasy"+path.Ext(link))
 	if err := download.DownloadFile(link, downloadPath, "", ""); err != nil {
@@ -61,28 +121,99 @@ func Download(pinnedVersion string, g Git) (string, error) {
 	}
 	defer os.Remove(downloadPath)
 
-	baseDir := environment.GetBaseDir()
+	var digest string
+	if environment.VerifyCLI() {
+		digest, err = verifyDownload(r, version, link, downloadPath)
+		if err != nil {
+			return version, fmt.Errorf("failed to verify speakeasy cli: %w", err)
+		}
+
+		if err := environment.SetCLIDigest(digest); err != nil {
+			return version, fmt.Errorf("failed to record speakeasy cli digest: %w", err)
+		}
+	}
 
-	if err := extract(downloadPath, filepath.Join(baseDir, "bin")); err != nil {
+	if err := extract(downloadPath, installDir); err != nil {
 		return version, fmt.Errorf("failed to extract speakeasy cli: %w", err)
 	}
 
-	if err := os.Chmod(filepath.Join(baseDir, "bin", "speakeasy"), 0o755); err != nil {
+	if err := os.Chmod(filepath.Join(installDir, "speakeasy"), 0o755); err != nil {
 		return version, fmt.Errorf("failed to set permissions on speakeasy cli: %w", err)
 	}
 
-	fmt.Println("Extracted speakeasy cli to: ", filepath.Join(baseDir, "bin"))
+	logging.With("version", version, "path", installDir).Info("Extracted speakeasy cli")
+
+	if cache != nil {
+		if err := cache.store(version, downloadPath, archiveName(link)); err != nil {
+			logging.Info("Failed to update speakeasy cli cache: %v", err)
+		}
+
+		return version, linkBinary(cache.binary(version), binary)
+	}
 
 	return version, nil
 }
 
-func runSpeakeasyCommand(args ...string) (string, error) {
+// useCachedRelease links the cached cli of the version, returning whether it
+// was used. With verify_cli on, the cached release archive is checked against
+// freshly fetched release checksums and extracted again, so that a cached
+// binary is never run before being verified.
+func useCachedRelease(c *toolCache, r release, version, link, binary string) (bool, error) {
+	if !environment.VerifyCLI() {
+		cached, ok := c.lookup(version)
+		if !ok {
+			return false, nil
+		}
+
+		logging.With("version", version, "path", cached).Info("Using cached speakeasy cli")
+
+		return true, linkBinary(cached, binary)
+	}
+
+	archive, ok := c.archive(version, archiveName(link))
+	if !ok {
+		return false, nil
+	}
+
+	digest, err := verifyDownload(r, version, link, archive)
+	if err != nil {
+		c.remove(version, err.Error())
+		return false, nil
+	}
+
+	// The cached binary may have been replaced, possibly with a link to another
+	// file that extracting would overwrite
+	if err := os.Remove(c.binary(version)); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to replace cached speakeasy cli: %w", err)
+	}
+
+	if err := extract(archive, c.dir(version)); err != nil {
+		c.remove(version, err.Error())
+		return false, nil
+	}
+
+	if err := os.Chmod(c.binary(version), 0o755); err != nil {
+		return false, fmt.Errorf("failed to set permissions on speakeasy cli: %w", err)
+	}
+
+	if err := environment.SetCLIDigest(digest); err != nil {
+		return false, fmt.Errorf("failed to record speakeasy cli digest: %w", err)
+	}
+
+	c.touch(version)
+
+	logging.With("version", version, "path", c.binary(version)).Info("Using verified cached speakeasy cli")
+
+	return true, linkBinary(c.binary(version), binary)
+}
+
+func runSpeakeasyCommand(ctx context.Context, args ...string) (string, error) {
 	baseDir := environment.GetBaseDir()
 	extraRunEnvVars := environment.SpeakeasyEnvVars()
 	cmdPath := filepath.Join(baseDir, "bin", "speakeasy")
 	logging.Info("The command path being executed: %s", cmdPath)
 	logging.Info("The command args: %s", args)
-	cmd := exec.Command(cmdPath, args...)
+	cmd := process.Command(ctx, process.PhaseGenerate, cmdPath, args...)
 	cmd.Dir = environment.GetRepoPath()
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, "SPEAKEASY_RUN_LOCATION=action")
@@ -93,12 +224,15 @@ func runSpeakeasyCommand(args ...string) (string, error) {
 	// Stream output in real-time so it appears in CI logs as the command
 	// runs, rather than buffering everything until completion.
 	var buf bytes.Buffer
-	cmd.Stdout = io.MultiWriter(os.Stdout, &buf)
-	cmd.Stderr = io.MultiWriter(os.Stderr, &buf)
+	stdout, stderr := redact.NewWriter(os.Stdout), redact.NewWriter(os.Stderr)
+	defer stdout.Close()
+	defer stderr.Close()
+	cmd.Stdout = io.MultiWriter(stdout, &buf)
+	cmd.Stderr = io.MultiWriter(stderr, &buf)
 
 	err := cmd.Run()
 	if err != nil {
-		return buf.String(), fmt.Errorf("error running speakeasy command: speakeasy %s - %w", strings.Join(args, " "), err)
+		return buf.String(), redact.Error(fmt.Errorf("error running speakeasy command: speakeasy %s - %w", strings.Join(args, " "), err))
 	}
 
 	return buf.String(), nil
@@ -215,7 +349,7 @@ type emptyCommitRequest struct {
 	RepoName string `json:"repo_name"`
 }
 
-func FireEmptyCommit(org, repo, branch string) error {
+func FireEmptyCommit(ctx context.Context, org, repo, branch string) error {
 	apiURL := "https://api.speakeasy.com/v1/github/empty_commit"
 
 	// Create the request body
@@ -253,7 +387,7 @@ func FireEmptyCommit(org, repo, branch string) error {
 	return nil
 }
 
-func CheckFreeUsageAccess() (bool, error) {
+func CheckFreeUsageAccess(ctx context.Context) (bool, error) {
 	apiURL := "https://api.speakeasyapi.dev/v1/workspace/access?passive=true"
 
 	req, err := http.NewRequest("GET", apiURL, nil)
@@ -289,7 +423,7 @@ func CheckFreeUsageAccess() (bool, error) {
 	return accessDetails.GenerationAllowed, nil
 }
 
-func Tag(tags, sources, codeSamples []string) error {
+func Tag(ctx context.Context, tags, sources, codeSamples []string) error {
 	args := []string{"tag", "promote"}
 
 	if len(tags) == 0 {
@@ -307,22 +441,24 @@ func Tag(tags, sources, codeSamples []string) error {
 	}
 
 	args = append(args, "-t", strings.Join(tags, ","))
-	_, err := runSpeakeasyCommand(args...)
+	_, err := runSpeakeasyCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("error running speakeasy tag: %w", err)
 	}
 
+	summary.AddTagPromotion(summary.TagPromotion{Tags: tags, Sources: sources, CodeSamples: codeSamples})
+
 	return nil
 }
 
-func Test(target string) error {
+func Test(ctx context.Context, target string) error {
 	args := []string{"test"}
 
 	if target != "all" {
 		args = append(args, "-t", target)
 	}
 
-	_, err := runSpeakeasyCommand(args...)
+	_, err := runSpeakeasyCommand(ctx, args...)
 	if err != nil {
 		return fmt.Errorf("error running speakeasy test for target %s: %w", target, err)
 	}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+)
+
+// validationFindingRegex matches the spec validation findings printed by the
+// CLI, e.g. "validation warn: [line 12] rule-id - message", where the location
+// may also be given as "[path/to/openapi.yaml:12:5]".
+var validationFindingRegex = regexp.MustCompile(`(?m)validation (error|warn|warning|hint): \[(?:line (\d+)|([^\]\s:]+):(\d+)(?::(\d+))?)\] (\S+) - (.*?)\s*$`)
+
+type validationFinding struct {
+	Severity string
+	File     string
+	Line     int
+	Column   int
+	Rule     string
+	Message  string
+}
+
+func parseValidationFindings(out string) []validationFinding {
+	var findings []validationFinding
+
+	for _, match := range validationFindingRegex.FindAllStringSubmatch(out, -1) {
+		finding := validationFinding{
+			Severity: match[1],
+			File:     match[3],
+			Rule:     match[6],
+			Message:  match[7],
+		}
+		if finding.Severity == "warning" {
+			finding.Severity = "warn"
+		}
+
+		line := match[2]
+		if line == "" {
+			line = match[4]
+		}
+		finding.Line, _ = strconv.Atoi(line)
+		finding.Column, _ = strconv.Atoi(match[5])
+
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// annotateValidationFindings surfaces the spec validation findings in the
+// output as annotations on the workflow run.
+func annotateValidationFindings(out string) {
+	for _, finding := range parseValidationFindings(out) {
+		file := finding.File
+		if file == "" {
+			file = localDocLocation()
+		}
+
+		annotation := logging.Annotation{
+			Title:  finding.Rule,
+			File:   repoRelativePath(file),
+			Line:   finding.Line,
+			Column: finding.Column,
+		}
+
+		switch finding.Severity {
+		case "error":
+			annotation.Error("%s", finding.Message)
+		case "warn":
+			annotation.Warning("%s", finding.Message)
+		default:
+			annotation.Notice("%s", finding.Message)
+		}
+	}
+}
+
+// localDocLocation returns the OpenAPI document being validated when it is a
+// file in the repository.
+func localDocLocation() string {
+	location := environment.GetOpenAPIDocLocation()
+	if location == "" {
+		return ""
+	}
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" {
+		return ""
+	}
+
+	return filepath.Join(environment.GetWorkspace(), "repo", location)
+}
+
+// repoRelativePath returns the path of the file relative to the root of the
+// repository, as annotations expect. Relative paths printed by the CLI are
+// relative to the working directory it ran in.
+func repoRelativePath(file string) string {
+	if file == "" {
+		return ""
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(environment.GetRepoPath(), file)
+	}
+
+	rel, err := filepath.Rel(filepath.Join(environment.GetWorkspace(), "repo"), file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidationFindings(t *testing.T) {
+	out := `INFO	Linting document...
+WARN	validation warn: [line 145] operation-success-response - operation updatePet must define at least a single 2xx or 3xx response
+ERROR	validation error: [openapi.yaml:12:5] validate-json-schema - expected string, got number
+INFO	validation hint: [line 7] missing-examples - Missing example for requestBody
+Done
+`
+
+	assert.Equal(t, []validationFinding{
+		{Severity: "warn", Line: 145, Rule: "operation-success-response", Message: "operation updatePet must define at least a single 2xx or 3xx response"},
+		{Severity: "error", File: "openapi.yaml", Line: 12, Column: 5, Rule: "validate-json-schema", Message: "expected string, got number"},
+		{Severity: "hint", Line: 7, Rule: "missing-examples", Message: "Missing example for requestBody"},
+	}, parseValidationFindings(out))
+
+	assert.Empty(t, parseValidationFindings("Generating SDK...\n"))
+}
+
+func TestRepoRelativePath(t *testing.T) {
+	t.Setenv("INPUT_WORKING_DIRECTORY", "api")
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{name: "relative to working directory", file: "specs/openapi.yaml", want: "api/specs/openapi.yaml"},
+		{name: "absolute in repo", file: workspace + "/repo/openapi.yaml", want: "openapi.yaml"},
+		{name: "outside repo", file: "/tmp/openapi.yaml", want: ""},
+		{name: "empty", file: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repoRelativePath(tt.file))
+		})
+	}
+}
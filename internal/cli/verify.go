@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/download"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+)
+
+// verifyDownload checks the downloaded archive against the checksums published
+// with the release, and the signature of the checksums if a signing key is
+// configured, returning the digest of the archive.
+func verifyDownload(r release, version, link, archivePath string) (string, error) {
+	checksums, err := releaseChecksums(r, version)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := download.VerifyChecksum(archivePath, archiveName(link), checksums)
+	if err != nil {
+		return "", err
+	}
+
+	logging.Info("Verified speakeasy cli %s (sha256:%s)", version, digest)
+
+	return digest, nil
+}
+
+// releaseChecksums downloads the checksums published with the release of the
+// version, checking their signature if a signing key is configured.
+func releaseChecksums(r release, version string) ([]byte, error) {
+	checksumsLink, signatureLink, err := r.GetChecksumsLinks(version)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums, err := fetch(checksumsLink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksums: %w", err)
+	}
+
+	if publicKey := environment.GetCLISigningPublicKey(); publicKey != "" {
+		if signatureLink == "" {
+			return nil, fmt.Errorf("no signature published for the checksums of speakeasy cli %s", version)
+		}
+
+		signature, err := fetch(signatureLink)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download checksums signature: %w", err)
+		}
+
+		if err := download.VerifySignature(checksums, signature, publicKey); err != nil {
+			return nil, fmt.Errorf("failed to verify checksums of speakeasy cli %s: %w", version, err)
+		}
+	}
+
+	return checksums, nil
+}
+
+// archiveName returns the name of the release archive at the link, as listed
+// in the checksums of the release.
+func archiveName(link string) string {
+	name := path.Base(link)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+
+	return name
+}
+
+func fetch(link string) ([]byte, error) {
+	f, err := os.CreateTemp("", "speakeasy-verify-*")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if err := download.DownloadFile(link, f.Name(), "", ""); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(f.Name())
+}
@@ -41,7 +41,7 @@ func getWorkflow() (*workflow.Workflow, error) {
 }
 
 func AssertTargetNamesSupported(workflowTargetNames []string) error {
-	supportedTargetNames := cli.GetSupportedTargetNames()
+	supportedTargetNames := cli.GetCapabilities().SupportedTargets
 	for _, workflowTargetName := range workflowTargetNames {
 		if !slices.Contains(supportedTargetNames, workflowTargetName) {
 			return fmt.Errorf("unsupported target: %s", workflowTargetName)
@@ -1,6 +1,7 @@
 package document
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/download"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,7 +23,7 @@ type file struct {
 	Token    string `yaml:"auth_token"`
 }
 
-func GetOpenAPIFileInfo() (string, string, error) {
+func GetOpenAPIFileInfo(ctx context.Context) (string, string, error) {
 	// TODO OPENAPI_DOC_LOCATION is deprecated and should be removed in the future
 	openapiFiles, err := getFiles(environment.GetOpenAPIDocs(), environment.GetOpenAPIDocLocation())
 	if err != nil {
@@ -41,7 +43,7 @@ func GetOpenAPIFileInfo() (string, string, error) {
 		basePath = filepath.Dir(filePath)
 	} else {
 		basePath = filepath.Dir(resolvedOpenAPIFiles[0])
-		filePath, err = mergeFiles(resolvedOpenAPIFiles)
+		filePath, err = mergeFiles(ctx, resolvedOpenAPIFiles)
 		if err != nil {
 			return "", "", err
 		}
@@ -58,7 +60,7 @@ func GetOpenAPIFileInfo() (string, string, error) {
 	}
 
 	if len(resolvedOverlayFiles) > 0 {
-		filePath, err = applyOverlay(filePath, resolvedOverlayFiles)
+		filePath, err = applyOverlay(ctx, filePath, resolvedOverlayFiles)
 		if err != nil {
 			return "", "", err
 		}
@@ -96,7 +98,7 @@ func GetOpenAPIFileInfo() (string, string, error) {
 	return filePath, version, nil
 }
 
-func mergeFiles(files []string) (string, error) {
+func mergeFiles(ctx context.Context, files []string) (string, error) {
 	outPath := filepath.Join(environment.GetWorkspace(), ".openapi", "openapi_merged")
 
 	if err := os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
@@ -108,14 +110,14 @@ func mergeFiles(files []string) (string, error) {
 		return "", fmt.Errorf("failed to get absolute path for openapi file: %w", err)
 	}
 
-	if err := cli.MergeDocuments(files, absOutPath); err != nil {
+	if err := cli.MergeDocuments(ctx, files, absOutPath); err != nil {
 		return "", fmt.Errorf("failed to merge openapi files: %w", err)
 	}
 
 	return absOutPath, nil
 }
 
-func applyOverlay(filePath string, overlayFiles []string) (string, error) {
+func applyOverlay(ctx context.Context, filePath string, overlayFiles []string) (string, error) {
 	for i, overlayFile := range overlayFiles {
 		outPath := filepath.Join(environment.GetWorkspace(), "openapi", fmt.Sprintf("openapi_overlay_%v", i))
 
@@ -128,7 +130,7 @@ func applyOverlay(filePath string, overlayFiles []string) (string, error) {
 			return "", fmt.Errorf("failed to get absolute path for openapi overlay file: %w", err)
 		}
 
-		if err := cli.ApplyOverlay(overlayFile, filePath, outPathAbs); err != nil {
+		if err := cli.ApplyOverlay(ctx, overlayFile, filePath, outPathAbs); err != nil {
 			return "", fmt.Errorf("failed to apply overlay: %w", err)
 		}
 
@@ -147,7 +149,7 @@ func resolveFiles(files []file, typ string) ([]string, error) {
 		localPath := filepath.Join(workspace, "repo", file.Location)
 
 		if _, err := os.Stat(localPath); err == nil {
-			fmt.Printf("Found local %s file: %s\n", typ, localPath)
+			logging.With("path", localPath).Info("Found local %s file", typ)
 			absPath, err := filepath.Abs(localPath)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get absolute path for %s file: %w", localPath, err)
@@ -160,7 +162,7 @@ func resolveFiles(files []file, typ string) ([]string, error) {
 				return nil, fmt.Errorf("failed to parse %s url: %w", typ, err)
 			}
 
-			fmt.Printf("Downloading %s file from: %s\n", typ, u.String())
+			logging.With("url", u.String()).Info("Downloading %s file", typ)
 
 			filePath := filepath.Join(environment.GetWorkspace(), typ, fmt.Sprintf("%s_%d", typ, i))
 
@@ -0,0 +1,87 @@
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// ParseChecksums parses a checksums file in the sha256sum format, returning the
+// checksum of each file by name.
+func ParseChecksums(data []byte) map[string]string {
+	checksums := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+
+	return checksums
+}
+
+// VerifyChecksum checks the file at path against the checksum of the named file
+// in the checksums file, returning its digest.
+func VerifyChecksum(path, name string, checksums []byte) (string, error) {
+	expected, ok := ParseChecksums(checksums)[name]
+	if !ok {
+		return "", fmt.Errorf("no checksum found for %s", name)
+	}
+
+	digest, err := SHA256File(path)
+	if err != nil {
+		return "", err
+	}
+
+	if digest != expected {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, digest)
+	}
+
+	return digest, nil
+}
+
+// SHA256File returns the hex encoded SHA256 digest of the file at path.
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifySignature checks the detached signature of data, armored or binary,
+// was made by one of the keys of the armored public key ring.
+func VerifySignature(data, signature []byte, armoredPublicKey string) error {
+	keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredPublicKey))
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyRing, bytes.NewReader(data), bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package download
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChecksums(t *testing.T) {
+	checksums := ParseChecksums([]byte("ABC  acme_Darwin_x86_64.tar.gz\ndef *acme_Linux_arm64.tar.gz\n\ninvalid line here\n"))
+
+	assert.Equal(t, map[string]string{
+		"acme_Darwin_x86_64.tar.gz": "abc",
+		"acme_Linux_arm64.tar.gz":   "def",
+	}, checksums)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "speakeasy_linux_amd64.zip")
+	require.NoError(t, os.WriteFile(path, []byte("speakeasy"), 0o600))
+
+	sum := sha256.Sum256([]byte("speakeasy"))
+	digest := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+	}{
+		{
+			name:      "matches",
+			checksums: digest + "  speakeasy_linux_amd64.zip\n",
+		},
+		{
+			name:      "mismatch",
+			checksums: "0000  speakeasy_linux_amd64.zip\n",
+			wantErr:   "checksum mismatch for speakeasy_linux_amd64.zip",
+		},
+		{
+			name:      "missing",
+			checksums: digest + "  speakeasy_darwin_arm64.zip\n",
+			wantErr:   "no checksum found for speakeasy_linux_amd64.zip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyChecksum(path, "speakeasy_linux_amd64.zip", []byte(tt.checksums))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, digest, got)
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	entity, err := openpgp.NewEntity("Speakeasy", "", "release@example.com", nil)
+	require.NoError(t, err)
+
+	var publicKey bytes.Buffer
+	w, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	data := []byte("abc  speakeasy_linux_amd64.zip\n")
+
+	var signature, armoredSignature bytes.Buffer
+	require.NoError(t, openpgp.DetachSign(&signature, entity, bytes.NewReader(data), nil))
+	require.NoError(t, openpgp.ArmoredDetachSign(&armoredSignature, entity, bytes.NewReader(data), nil))
+
+	assert.NoError(t, VerifySignature(data, signature.Bytes(), publicKey.String()))
+	assert.NoError(t, VerifySignature(data, armoredSignature.Bytes(), publicKey.String()))
+	assert.ErrorContains(t, VerifySignature([]byte("tampered"), signature.Bytes(), publicKey.String()), "invalid signature")
+	assert.ErrorContains(t, VerifySignature(data, signature.Bytes(), "not a key"), "failed to read public key")
+}
@@ -21,6 +21,15 @@ const (
 	ModeTest   Mode = "test"
 )
 
+type CLISource string
+
+const (
+	CLISourceGitHub CLISource = "github"
+	CLISourceMirror CLISource = "mirror"
+	CLISourceFile   CLISource = "file"
+	CLISourcePath   CLISource = "path"
+)
+
 type Action string
 
 const (
@@ -34,6 +43,9 @@ const (
 	ActionPublishEvent       Action = "publish-event"
 	ActionTag                Action = "tag"
 	ActionTest               Action = "test"
+	ActionRollback           Action = "rollback"
+	ActionHomebrewFormula    Action = "homebrew-formula"
+	ActionPreviewCleanup     Action = "preview-cleanup"
 )
 
 const (
@@ -61,6 +73,15 @@ func IsDebugMode() bool {
 	return os.Getenv("INPUT_DEBUG") == "true" || os.Getenv("RUNNER_DEBUG") == "1"
 }
 
+// GetLogFormat returns the format of the action's log, "text" or "json".
+func GetLogFormat() string {
+	if strings.ToLower(strings.TrimSpace(os.Getenv("INPUT_LOG_FORMAT"))) == "json" {
+		return "json"
+	}
+
+	return "text"
+}
+
 func IsTestMode() bool {
 	return GetMode() == ModeTest
 }
@@ -213,6 +234,18 @@ func GetGPGFingerprint() string {
 	return os.Getenv("INPUT_GPG_FINGERPRINT")
 }
 
+func GetTagSigningKey() string {
+	return os.Getenv("INPUT_TAG_SIGNING_KEY")
+}
+
+func GetTagSigningFormat() string {
+	return strings.ToLower(strings.TrimSpace(os.Getenv("INPUT_TAG_SIGNING_FORMAT")))
+}
+
+func GetTagSigningPassphrase() string {
+	return os.Getenv("INPUT_TAG_SIGNING_PASSPHRASE")
+}
+
 func GetInvokeTime() time.Time {
 	return invokeTime
 }
@@ -241,6 +274,156 @@ func GetSDKChangelog() string {
 	return os.Getenv("INPUT_ENABLE_SDK_CHANGELOG")
 }
 
+func AttachReleaseArtifacts() bool {
+	return os.Getenv("INPUT_ATTACH_RELEASE_ARTIFACTS") == "true"
+}
+
+func GetRollbackVersion() string {
+	return strings.TrimPrefix(os.Getenv("INPUT_ROLLBACK_VERSION"), "v")
+}
+
+// ShouldDeleteRolledBackRelease returns true unless rolled back releases are
+// configured to be marked as yanked instead of deleted.
+func ShouldDeleteRolledBackRelease() bool {
+	return os.Getenv("INPUT_ROLLBACK_RELEASE") != "mark"
+}
+
+func DraftReleases() bool {
+	return os.Getenv("INPUT_DRAFT_RELEASES") == "true"
+}
+
+// GetMirrorRepository returns the repository (in owner/repo form) the target
+// output directory dir is mirrored to, or an empty string if it isn't mirrored.
+// Mirrors are configured as path=owner/repo entries, with paths relative to the
+// root of the repository.
+func GetMirrorRepository(dir string) string {
+	dir = filepath.Clean(dir)
+	for _, entry := range parseArrayInput(os.Getenv("INPUT_MIRROR_REPOSITORIES")) {
+		path, repo, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if filepath.Clean(strings.TrimSpace(path)) == dir {
+			return strings.TrimSpace(repo)
+		}
+	}
+
+	return ""
+}
+
+// GetMirrorAccessToken returns the token used to push to mirror repositories,
+// falling back to the github access token.
+func GetMirrorAccessToken() string {
+	if token := os.Getenv("INPUT_MIRROR_ACCESS_TOKEN"); token != "" {
+		return token
+	}
+
+	return GetAccessToken()
+}
+
+// GetRegistryBaseURL returns the base URL the named package registry is queried
+// at, from the `registry=url` lines of the registry_base_urls input.
+func GetRegistryBaseURL(registry string) string {
+	for _, entry := range parseArrayInput(os.Getenv("INPUT_REGISTRY_BASE_URLS")) {
+		name, baseURL, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(name) == registry {
+			return strings.TrimSuffix(strings.TrimSpace(baseURL), "/")
+		}
+	}
+
+	return ""
+}
+
+// RegistryOverride replaces the public package registry of a target, e.g. with
+// a private npm feed.
+type RegistryOverride struct {
+	// Name is the registry name recorded for publish events.
+	Name string `json:"name,omitempty"`
+	// BaseURL is the registry API packages are published to and queried at.
+	BaseURL string `json:"base_url,omitempty"`
+	// PackageURL links to a version of the package, with {name}, {version} and
+	// {path} placeholders.
+	PackageURL string `json:"package_url,omitempty"`
+	// Publish overrides whether the target is published.
+	Publish *bool `json:"publish,omitempty"`
+}
+
+// GetRegistryOverride returns the registry override of the target from the
+// registry_overrides input, a JSON object keyed by target.
+func GetRegistryOverride(target string) (RegistryOverride, bool) {
+	input := os.Getenv("INPUT_REGISTRY_OVERRIDES")
+	if strings.TrimSpace(input) == "" {
+		return RegistryOverride{}, false
+	}
+
+	var overrides map[string]RegistryOverride
+	if err := json.Unmarshal([]byte(input), &overrides); err != nil {
+		fmt.Println("Error parsing registry_overrides input:", err)
+		return RegistryOverride{}, false
+	}
+
+	override, ok := overrides[target]
+	override.BaseURL = strings.TrimSuffix(override.BaseURL, "/")
+
+	return override, ok
+}
+
+func PreviewVersions() bool {
+	return os.Getenv("INPUT_PREVIEW_VERSIONS") == "true"
+}
+
+// GetPullRequestNumber returns the number of the PR from the pr_number input,
+// falling back to the PR of the triggering pull_request event.
+func GetPullRequestNumber() int {
+	if number, err := strconv.Atoi(os.Getenv("INPUT_PR_NUMBER")); err == nil {
+		return number
+	}
+
+	data, err := os.ReadFile(GetWorkflowEventPayloadPath())
+	if err != nil {
+		return 0
+	}
+
+	var event struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		fmt.Println("Error parsing event JSON:", err)
+		return 0
+	}
+
+	return event.Number
+}
+
+func SkipRegistryCheck() bool {
+	return os.Getenv("INPUT_SKIP_REGISTRY_CHECK") == "true"
+}
+
+func GetHomebrewTap() string {
+	return os.Getenv("INPUT_HOMEBREW_TAP")
+}
+
+func GetHomebrewFormulaName() string {
+	return os.Getenv("INPUT_HOMEBREW_FORMULA")
+}
+
+func GetHomebrewFormulaDescription() string {
+	return os.Getenv("INPUT_HOMEBREW_FORMULA_DESCRIPTION")
+}
+
+// GetHomebrewTapAccessToken returns the token used to commit to the homebrew
+// tap, falling back to the github access token.
+func GetHomebrewTapAccessToken() string {
+	if token := os.Getenv("INPUT_HOMEBREW_TAP_ACCESS_TOKEN"); token != "" {
+		return token
+	}
+
+	return GetAccessToken()
+}
+
 func SkipRelease() bool {
 	return os.Getenv("INPUT_SKIP_RELEASE") == "true"
 }
@@ -369,6 +552,87 @@ func SetCLIVersionToUse(version string) error {
 	return os.Setenv("PINNED_VERSION", version)
 }
 
+// VerifyCLI returns whether the downloaded speakeasy cli is verified against the
+// checksums published with its release, which is on unless verify_cli is false.
+func VerifyCLI() bool {
+	return os.Getenv("INPUT_VERIFY_CLI") != "false"
+}
+
+// GetCLISigningPublicKey returns the armored PGP public key the checksums of
+// the speakeasy cli release must be signed with, if any.
+func GetCLISigningPublicKey() string {
+	return os.Getenv("INPUT_CLI_SIGNING_PUBLIC_KEY")
+}
+
+// SetCLIDigest records the sha256 digest of the verified speakeasy cli archive.
+func SetCLIDigest(digest string) error {
+	return os.Setenv("SPEAKEASY_CLI_SHA256", digest)
+}
+
+func GetCLIDigest() string {
+	return os.Getenv("SPEAKEASY_CLI_SHA256")
+}
+
+// GetCLISource returns where the speakeasy cli is installed from, defaulting to
+// the releases of speakeasy-api/speakeasy on github.com.
+func GetCLISource() CLISource {
+	source := os.Getenv("INPUT_CLI_SOURCE")
+	if source == "" {
+		return CLISourceGitHub
+	}
+
+	return CLISource(strings.ToLower(strings.TrimSpace(source)))
+}
+
+// GetCLIMirrorURL returns the URL template speakeasy cli release assets are
+// downloaded from when using a mirror.
+func GetCLIMirrorURL() string {
+	return os.Getenv("INPUT_CLI_MIRROR_URL")
+}
+
+// GetCLIFile returns the path to a provisioned speakeasy cli binary or release
+// archive.
+func GetCLIFile() string {
+	return os.Getenv("INPUT_CLI_FILE")
+}
+
+// GetCLIVersionIndex returns the path or URL of the index speakeasy cli
+// versions are resolved from instead of the github.com API.
+func GetCLIVersionIndex() string {
+	return os.Getenv("INPUT_CLI_VERSION_INDEX")
+}
+
+// GetCLICacheDir returns the directory speakeasy cli binaries are cached in by
+// version, defaulting to the runner tool cache.
+func GetCLICacheDir() string {
+	if dir := os.Getenv("INPUT_CLI_CACHE_DIR"); dir != "" {
+		return dir
+	}
+
+	return os.Getenv("RUNNER_TOOL_CACHE")
+}
+
+// GetPhaseTimeout returns the timeout of the commands run in the phase,
+// configured as phase=duration entries, or 0 if the phase has no timeout.
+func GetPhaseTimeout(phase string) time.Duration {
+	for _, entry := range parseArrayInput(os.Getenv("INPUT_TIMEOUTS")) {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || strings.TrimSpace(name) != phase {
+			continue
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			fmt.Printf("Error parsing timeout of %s: %v\n", phase, err)
+			return 0
+		}
+
+		return timeout
+	}
+
+	return 0
+}
+
 func parseArrayInput(input string) []string {
 	if input == "" {
 		return []string{}
@@ -2,9 +2,12 @@ package environment
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetSourceBranch(t *testing.T) {
@@ -369,3 +372,59 @@ func TestShouldSkipReleasing(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMirrorRepository(t *testing.T) {
+	t.Setenv("INPUT_MIRROR_REPOSITORIES", "sdks/php=org/php-sdk\n ./sdks/swift = org/swift-sdk \ninvalid")
+
+	assert.Equal(t, "org/php-sdk", GetMirrorRepository("sdks/php"))
+	assert.Equal(t, "org/php-sdk", GetMirrorRepository("./sdks/php/"))
+	assert.Equal(t, "org/swift-sdk", GetMirrorRepository("sdks/swift"))
+	assert.Equal(t, "", GetMirrorRepository("sdks/go"))
+	assert.Equal(t, "", GetMirrorRepository("."))
+}
+
+func TestGetRegistryBaseURL(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "npm=https://npm.example.com/\n pypi = https://pypi.example.com \ninvalid")
+
+	assert.Equal(t, "https://npm.example.com", GetRegistryBaseURL("npm"))
+	assert.Equal(t, "https://pypi.example.com", GetRegistryBaseURL("pypi"))
+	assert.Equal(t, "", GetRegistryBaseURL("nuget"))
+}
+
+func TestGetRegistryOverride(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_OVERRIDES", `{"typescript": {"name": "github-packages", "base_url": "https://npm.pkg.github.com/", "publish": true}}`)
+
+	override, ok := GetRegistryOverride("typescript")
+	assert.True(t, ok)
+	assert.Equal(t, "github-packages", override.Name)
+	assert.Equal(t, "https://npm.pkg.github.com", override.BaseURL)
+	assert.True(t, *override.Publish)
+
+	_, ok = GetRegistryOverride("python")
+	assert.False(t, ok)
+}
+
+func TestGetPullRequestNumber(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(`{"action":"closed","number":42}`), 0o600))
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+
+	t.Setenv("INPUT_PR_NUMBER", "")
+	assert.Equal(t, 42, GetPullRequestNumber())
+
+	t.Setenv("INPUT_PR_NUMBER", "7")
+	assert.Equal(t, 7, GetPullRequestNumber())
+
+	t.Setenv("INPUT_PR_NUMBER", "")
+	t.Setenv("GITHUB_EVENT_PATH", filepath.Join(t.TempDir(), "missing.json"))
+	assert.Equal(t, 0, GetPullRequestNumber())
+}
+
+func TestGetPhaseTimeout(t *testing.T) {
+	t.Setenv("INPUT_TIMEOUTS", "generate=2h\nsetup = 15m\ngit=invalid")
+
+	assert.Equal(t, 2*time.Hour, GetPhaseTimeout("generate"))
+	assert.Equal(t, 15*time.Minute, GetPhaseTimeout("setup"))
+	assert.Equal(t, time.Duration(0), GetPhaseTimeout("git"))
+	assert.Equal(t, time.Duration(0), GetPhaseTimeout("release"))
+}
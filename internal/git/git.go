@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/url"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -27,6 +26,8 @@ import (
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
 	"github.com/speakeasy-api/sdk-generation-action/internal/versionbumps"
 	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
 	"github.com/speakeasy-api/versioning-reports/versioning"
@@ -40,12 +41,15 @@ type Git struct {
 	repo        *git.Repository
 	client      *github.Client
 	storerLog   *loggingStorer
+	// ctx bounds the git commands run against the repository
+	ctx context.Context
 }
 
 const (
 	speakeasyBotName       = "speakeasybot"
 	speakeasyBotAlias      = "speakeasy-bot"
 	speakeasyGithubBotName = "speakeasy-github[bot]"
+	speakeasyBotEmail      = "bot@speakeasyapi.dev"
 )
 
 var managedAutomationUsers = []string{speakeasyGithubBotName, speakeasyBotName, speakeasyBotAlias}
@@ -63,6 +67,22 @@ func New(accessToken string) *Git {
 	}
 }
 
+// WithContext returns a copy of the client running git commands with ctx, so
+// that they are stopped when the action is cancelled.
+func (g *Git) WithContext(ctx context.Context) *Git {
+	c := *g
+	c.ctx = ctx
+	return &c
+}
+
+func (g *Git) context() context.Context {
+	if g.ctx == nil {
+		return process.Context()
+	}
+
+	return g.ctx
+}
+
 func (g *Git) CloneRepo() error {
 	githubURL := os.Getenv("GITHUB_SERVER_URL")
 	githubRepoLocation := os.Getenv("GITHUB_REPOSITORY")
@@ -126,7 +146,7 @@ func (g *Git) configureSystemGitAuth(repoDir string) error {
 	authenticatedPrefix := fmt.Sprintf("https://gen:%s@%s/", g.accessToken, host)
 	originalPrefix := fmt.Sprintf("https://%s/", host)
 
-	cmd := exec.Command("git", "config", "--local",
+	cmd := process.Command(g.context(), process.PhaseGit, "git", "config", "--local",
 		fmt.Sprintf("url.%s.insteadOf", authenticatedPrefix),
 		originalPrefix,
 	)
@@ -208,7 +228,7 @@ func (g *Git) CheckDirDirty(dir string, ignoreChangePatterns map[string]string)
 		return false, "", nil
 	}
 
-	diffOutput, err := runGitCommand("diff", "--word-diff=porcelain")
+	diffOutput, err := runGitCommand(g.context(), "diff", "--word-diff=porcelain")
 	if err != nil {
 		return false, "", fmt.Errorf("error running git diff: %w", err)
 	}
@@ -330,7 +350,7 @@ func (g *Git) Reset(args ...string) error {
 
 	logging.Info("Running git  %s", strings.Join(args, " "))
 
-	cmd := exec.Command("git", args...)
+	cmd := process.Command(g.context(), process.PhaseGit, "git", args...)
 	cmd.Dir = environment.GetRepoPath()
 	cmd.Env = os.Environ()
 	output, err := cmd.CombinedOutput()
@@ -479,7 +499,7 @@ func (g *Git) findNonCICommits(branchName, defaultBranch string) ([]string, erro
 	}
 
 	revSpec := fmt.Sprintf("origin/%s..%s", defaultBranch, branchName)
-	cmd := exec.Command("git", "log", revSpec, "--pretty=format:%H%x09%an%x09%cn%x09%s")
+	cmd := process.Command(g.context(), process.PhaseGit, "git", "log", revSpec, "--pretty=format:%H%x09%an%x09%cn%x09%s")
 	cmd.Dir = environment.GetRepoPath()
 	cmd.Env = os.Environ()
 
@@ -756,7 +776,7 @@ func (g *Git) getOrCreateRef(commitRef string) (ref *github.Reference, err error
 
 func (g *Git) Add(arg string) error {
 	// We execute this manually because go-git doesn't properly support gitignore
-	cmd := exec.Command("git", "add", arg)
+	cmd := process.Command(g.context(), process.PhaseGit, "git", "add", arg)
 	cmd.Dir = environment.GetRepoPath()
 	cmd.Env = os.Environ()
 	output, err := cmd.CombinedOutput()
@@ -1014,7 +1034,7 @@ func (g *Git) tryGeneratePRDescriptionViaCLI(info PRInfo) *cli.PRDescriptionOutp
 	}
 	input.ActionRunURL = environment.GetActionRunURL(environment.GetRepo())
 
-	output, err := cli.GeneratePRDescription(input)
+	output, err := cli.GeneratePRDescription(g.context(), input)
 	if err != nil {
 		logging.Info("Error generating PR description via CLI: %v", err)
 		return nil
@@ -1059,7 +1079,7 @@ func (g *Git) generateGeneratorChangelogForOldCLIVersions(info PRInfo, previousG
 			}
 		}
 
-		versionChangelog, err := cli.GetChangelog(language, info.ReleaseInfo.GenerationVersion, "", targetVersions, previousVersions)
+		versionChangelog, err := cli.GetChangelog(g.context(), language, info.ReleaseInfo.GenerationVersion, "", targetVersions, previousVersions)
 		if err != nil {
 			return changelog, fmt.Errorf("failed to get changelog for language %s: %w", language, err)
 		}
@@ -1069,7 +1089,7 @@ func (g *Git) generateGeneratorChangelogForOldCLIVersions(info PRInfo, previousG
 	if changelog == "" {
 		// Not using granular version, grab old changelog
 		var err error
-		changelog, err = cli.GetChangelog("", info.ReleaseInfo.GenerationVersion, info.PreviousGenVersion, nil, nil)
+		changelog, err = cli.GetChangelog(g.context(), "", info.ReleaseInfo.GenerationVersion, info.PreviousGenVersion, nil, nil)
 		if err != nil {
 			return changelog, fmt.Errorf("failed to get changelog: %w", err)
 		}
@@ -1174,7 +1194,8 @@ func (g *Git) CreateSuggestionPR(branchName, output string) (*int, string, error
 		targetBaseBranch = strings.TrimPrefix(targetBaseBranch, "refs/heads/")
 	}
 
-	fmt.Println(body, branchName, title, targetBaseBranch)
+	logging.With("branch", branchName, "base", targetBaseBranch, "title", title).Info("Creating PR")
+	logging.Debug("PR body: %s", body)
 
 	pr, _, err := g.client.PullRequests.Create(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), &github.NewPullRequest{
 		Title:               github.String(title),
@@ -1204,13 +1225,23 @@ func (g *Git) WritePRBody(prNumber int, body string) error {
 	return nil
 }
 
+// ListIssueComments returns all the comments on the PR, across every page.
 func (g *Git) ListIssueComments(prNumber int) ([]*github.IssueComment, error) {
-	comments, _, err := g.client.Issues.ListComments(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), prNumber, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get PR comments: %w", err)
-	}
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	var comments []*github.IssueComment
+	for {
+		page, res, err := g.client.Issues.ListComments(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PR comments: %w", err)
+		}
+		comments = append(comments, page...)
 
-	return comments, nil
+		if res.NextPage == 0 {
+			return comments, nil
+		}
+		opts.Page = res.NextPage
+	}
 }
 
 func (g *Git) DeleteIssueComment(commentID int64) error {
@@ -1222,6 +1253,19 @@ func (g *Git) DeleteIssueComment(commentID int64) error {
 	return nil
 }
 
+func (g *Git) EditIssueComment(commentID int64, body string) error {
+	comment := &github.IssueComment{
+		Body: github.String(sanitizeExplanations(body)),
+	}
+
+	_, _, err := g.client.Issues.EditComment(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), commentID, comment)
+	if err != nil {
+		return fmt.Errorf("failed to edit issue comment: %w", err)
+	}
+
+	return nil
+}
+
 func (g *Git) WritePRComment(prNumber int, fileName, body string, line int) error {
 	pr, _, err := g.client.PullRequests.Get(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), prNumber)
 	if err != nil {
@@ -1282,14 +1326,14 @@ func (g *Git) MergeBranch(branchName string) (string, error) {
 		return "", fmt.Errorf("error checking out branch: %w", err)
 	}
 
-	output, err := runGitCommand("merge", branchName)
+	output, err := runGitCommand(g.context(), "merge", branchName)
 	if err != nil {
 		// This can happen if a "compile" has changed something unexpectedly. Add a "git status --porcelain" into the action output
-		debugOutput, _ := runGitCommand("status", "--porcelain")
+		debugOutput, _ := runGitCommand(g.context(), "status", "--porcelain")
 		if len(debugOutput) > 0 {
 			logging.Info("git status\n%s", debugOutput)
 		}
-		debugOutput, _ = runGitCommand("diff")
+		debugOutput, _ = runGitCommand(g.context(), "diff")
 		if len(debugOutput) > 0 {
 			logging.Info("git diff\n%s", debugOutput)
 		}
@@ -1356,6 +1400,45 @@ func (g *Git) GetDownloadLink(version string) (string, string, error) {
 	}
 }
 
+// GetChecksumsLinks returns the download links of the checksums file of the
+// speakeasy cli release with the given tag, and of its signature if one is
+// published.
+func (g *Git) GetChecksumsLinks(tag string) (string, string, error) {
+	release, _, err := g.client.Repositories.GetReleaseByTag(context.Background(), "speakeasy-api", "speakeasy", tag)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get speakeasy cli release %s: %w", tag, err)
+	}
+
+	checksums, signature := getChecksumsLinksFromRelease(release)
+	if checksums == "" {
+		return "", "", fmt.Errorf("no checksums published for speakeasy cli release %s", tag)
+	}
+
+	return checksums, signature, nil
+}
+
+func getChecksumsLinksFromRelease(release *github.RepositoryRelease) (string, string) {
+	var checksumsName, checksums, signature string
+	for _, asset := range release.Assets {
+		if strings.HasSuffix(asset.GetName(), "checksums.txt") {
+			checksumsName = asset.GetName()
+			checksums = asset.GetBrowserDownloadURL()
+		}
+	}
+
+	if checksumsName == "" {
+		return "", ""
+	}
+
+	for _, asset := range release.Assets {
+		if asset.GetName() == checksumsName+".sig" || asset.GetName() == checksumsName+".asc" {
+			signature = asset.GetBrowserDownloadURL()
+		}
+	}
+
+	return checksums, signature
+}
+
 func ArtifactMatchesRelease(assetName, goos, goarch string) bool {
 	assetNameLower := strings.ToLower(assetName)
 
@@ -1459,7 +1542,7 @@ func (g *Git) GetChangedFilesForPRorBranch() ([]string, *int, error) {
 
 		defaultBranch := "main"
 		if payload.Repository.DefaultBranch != "" {
-			fmt.Println("Default branch:", payload.Repository.DefaultBranch)
+			logging.Info("Default branch: %s", payload.Repository.DefaultBranch)
 			defaultBranch = payload.Repository.DefaultBranch
 		}
 
@@ -1610,13 +1693,20 @@ func (g *Git) GetCommitedFiles() ([]string, error) {
 func (g *Git) CreateTag(tag string, hash string) error {
 	logging.Info("Creating Tag %s from commit %s", tag, hash)
 
-	if _, err := g.repo.CreateTag(tag, plumbing.NewHash(hash), &git.CreateTagOptions{
+	tagger := &object.Signature{
+		Name:  speakeasyBotName,
+		Email: speakeasyBotEmail,
+		When:  time.Now(),
+	}
+
+	if TagSigningEnabled() {
+		if err := g.createSignedTag(tag, hash, tagger); err != nil {
+			logging.Info("Failed to create signed tag: %s", err)
+			return err
+		}
+	} else if _, err := g.repo.CreateTag(tag, plumbing.NewHash(hash), &git.CreateTagOptions{
 		Message: tag,
-		Tagger: &object.Signature{
-			Name:  speakeasyBotName,
-			Email: "bot@speakeasyapi.dev",
-			When:  time.Now(),
-		},
+		Tagger:  tagger,
 	}); err != nil {
 		logging.Info("Failed to create tag: %s", err)
 		return err
@@ -1629,7 +1719,7 @@ func (g *Git) CreateTag(tag string, hash string) error {
 func (g *Git) PushTag(tag string) error {
 	logging.Info("Pushing tag %s", tag)
 
-	_, err := runGitCommand("push", "origin", fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
+	_, err := runGitCommand(g.context(), "push", "origin", fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
 	if err != nil {
 		return err
 	}
@@ -1678,14 +1768,14 @@ func getSuggestPRTitlePrefix() string {
 	return speakeasySuggestPRTitle + environment.GetWorkflowName()
 }
 
-func runGitCommand(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+func runGitCommand(ctx context.Context, args ...string) (string, error) {
+	cmd := process.Command(ctx, process.PhaseGit, "git", args...)
 	cmd.Dir = filepath.Join(environment.GetWorkspace(), "repo")
 	var outb, errb bytes.Buffer
 	cmd.Stdout = &outb
 	cmd.Stderr = &errb
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run git command: %w - %s", err, errb.String())
+		return "", redact.Error(fmt.Errorf("failed to run git command: %w - %s", err, errb.String()))
 	}
 
 	return outb.String(), nil
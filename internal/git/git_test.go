@@ -808,6 +808,49 @@ func TestArtifactMatchesRelease(t *testing.T) {
 	}
 }
 
+func TestGetChecksumsLinksFromRelease(t *testing.T) {
+	asset := func(name string) *github.ReleaseAsset {
+		return &github.ReleaseAsset{Name: github.String(name), BrowserDownloadURL: github.String("https://example.com/" + name)}
+	}
+
+	tests := []struct {
+		name          string
+		assets        []*github.ReleaseAsset
+		wantChecksums string
+		wantSignature string
+	}{
+		{
+			name:          "checksums and signature",
+			assets:        []*github.ReleaseAsset{asset("speakeasy_linux_amd64.zip"), asset("checksums.txt"), asset("checksums.txt.sig")},
+			wantChecksums: "https://example.com/checksums.txt",
+			wantSignature: "https://example.com/checksums.txt.sig",
+		},
+		{
+			name:          "prefixed checksums with armored signature",
+			assets:        []*github.ReleaseAsset{asset("speakeasy_1.2.3_checksums.txt"), asset("speakeasy_1.2.3_checksums.txt.asc")},
+			wantChecksums: "https://example.com/speakeasy_1.2.3_checksums.txt",
+			wantSignature: "https://example.com/speakeasy_1.2.3_checksums.txt.asc",
+		},
+		{
+			name:          "checksums without signature",
+			assets:        []*github.ReleaseAsset{asset("checksums.txt")},
+			wantChecksums: "https://example.com/checksums.txt",
+		},
+		{
+			name:   "no checksums",
+			assets: []*github.ReleaseAsset{asset("speakeasy_linux_amd64.zip"), asset("checksums.txt.sig")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checksums, signature := getChecksumsLinksFromRelease(&github.RepositoryRelease{Assets: tt.assets})
+			assert.Equal(t, tt.wantChecksums, checksums)
+			assert.Equal(t, tt.wantSignature, signature)
+		})
+	}
+}
+
 // Test source-branch-aware branch naming
 func TestGit_FindOrCreateBranch_SourceBranchAware(t *testing.T) {
 	tests := []struct {
@@ -1602,3 +1645,25 @@ func TestCreateSuggestionPR_SourceBranchAware(t *testing.T) {
 		})
 	}
 }
+
+func TestGit_ListIssueComments_Paginates(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "acme")
+	t.Setenv("GITHUB_REPOSITORY", "acme/sdk")
+
+	g := &Git{client: newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<`+"http://"+r.Host+r.URL.Path+`?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":1,"body":"first"}]`))
+		case "2":
+			_, _ = w.Write([]byte(`[{"id":2,"body":"second"}]`))
+		}
+	})}
+
+	comments, err := g.ListIssueComments(7)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "second", comments[1].GetBody())
+}
@@ -0,0 +1,203 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"golang.org/x/oauth2"
+)
+
+const speakeasyHomebrewPRTitle = "chore: 🐝 Update Homebrew formula - "
+
+// GetReleaseAssets returns every asset attached to the release for the given
+// tag, paging through releases with many assets.
+func (g *Git) GetReleaseAssets(tag string) ([]*github.ReleaseAsset, error) {
+	release, err := g.getReleaseByTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, fmt.Errorf("no release found for tag %s", tag)
+	}
+
+	var assets []*github.ReleaseAsset
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, res, err := g.client.Repositories.ListReleaseAssets(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), release.GetID(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list assets of release %s: %w", tag, err)
+		}
+		assets = append(assets, page...)
+		if res == nil || res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+
+	return assets, nil
+}
+
+// DownloadReleaseAsset returns the contents of a release asset.
+func (g *Git) DownloadReleaseAsset(asset *github.ReleaseAsset) (io.ReadCloser, error) {
+	rc, _, err := g.client.Repositories.DownloadReleaseAsset(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), asset.GetID(), g.client.Client())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download release asset %s: %w", asset.GetName(), err)
+	}
+
+	return rc, nil
+}
+
+// CommitHomebrewFormula writes the formula to formulaPath in the tap
+// repository, either directly to its default branch or, if openPR is set, on a
+// branch with a PR to the default branch. It returns the URL of the commit or
+// PR, or an empty string if the formula is already up to date.
+func (g *Git) CommitHomebrewFormula(tap, formulaPath, formula, name, version string, openPR bool) (string, error) {
+	owner, repo, ok := strings.Cut(tap, "/")
+	if !ok || owner == "" || repo == "" {
+		return "", fmt.Errorf("invalid homebrew tap %q, expected owner/repo", tap)
+	}
+
+	ctx := context.Background()
+	client := g.homebrewTapClient()
+
+	tapRepo, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to get homebrew tap %s: %w", tap, err)
+	}
+	base := tapRepo.GetDefaultBranch()
+
+	content, _, err := getFileContent(ctx, client, owner, repo, formulaPath, base)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s from homebrew tap %s: %w", formulaPath, tap, err)
+	}
+	if content == formula {
+		logging.Info("Homebrew formula %s in %s is already up to date", formulaPath, tap)
+		return "", nil
+	}
+
+	branch := base
+	if openPR {
+		branch = fmt.Sprintf("speakeasy-homebrew-%s-v%s", name, version)
+		if err := ensureBranch(ctx, client, owner, repo, base, branch); err != nil {
+			return "", err
+		}
+	}
+
+	content, sha, err := getFileContent(ctx, client, owner, repo, formulaPath, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s from homebrew tap %s: %w", formulaPath, tap, err)
+	}
+
+	message := fmt.Sprintf("%s%s v%s", speakeasyHomebrewPRTitle, name, version)
+
+	// A rerun may find the formula already committed to the PR branch
+	if content != formula {
+		commit, _, err := client.Repositories.UpdateFile(ctx, owner, repo, formulaPath, &github.RepositoryContentFileOptions{
+			Message: github.String(message),
+			Content: []byte(formula),
+			SHA:     sha,
+			Branch:  github.String(branch),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to commit %s to homebrew tap %s: %w", formulaPath, tap, err)
+		}
+		if !openPR {
+			logging.Info("Committed homebrew formula %s to %s", formulaPath, tap)
+			return commit.GetHTMLURL(), nil
+		}
+	}
+
+	prs, _, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		Head:  fmt.Sprintf("%s:%s", owner, branch),
+		Base:  base,
+		State: "open",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list PRs in homebrew tap %s: %w", tap, err)
+	}
+	if len(prs) > 0 {
+		logging.Info("Found existing homebrew formula PR %s", prs[0].GetHTMLURL())
+		return prs[0].GetHTMLURL(), nil
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title:               github.String(message),
+		Body:                github.String(fmt.Sprintf("Updates the `%s` formula to v%s, generated from the release assets of %s.", name, version, tagURL("v"+version))),
+		Head:                github.String(branch),
+		Base:                github.String(base),
+		MaintainerCanModify: github.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create PR in homebrew tap %s: %w", tap, err)
+	}
+
+	logging.Info("Created homebrew formula PR %s", pr.GetHTMLURL())
+
+	return pr.GetHTMLURL(), nil
+}
+
+// getFileContent returns the content and blob SHA of a file at ref, or an
+// empty string and nil SHA if it doesn't exist.
+func getFileContent(ctx context.Context, client *github.Client, owner, repo, path, ref string) (string, *string, error) {
+	file, _, res, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if isNotFound(res) {
+			return "", nil, nil
+		}
+		return "", nil, err
+	}
+	if file == nil {
+		return "", nil, fmt.Errorf("%s is not a file", path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return content, file.SHA, nil
+}
+
+// ensureBranch creates branch from the head of base, leaving it untouched if
+// it already exists so reruns update the same PR.
+func ensureBranch(ctx context.Context, client *github.Client, owner, repo, base, branch string) error {
+	if _, res, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch); err == nil {
+		return nil
+	} else if !isNotFound(res) {
+		return fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+
+	baseRef, _, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+base)
+	if err != nil {
+		return fmt.Errorf("failed to get branch %s: %w", base, err)
+	}
+
+	if _, _, err := client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	}); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+func (g *Git) homebrewTapClient() *github.Client {
+	token := environment.GetHomebrewTapAccessToken()
+	if token == "" || token == g.accessToken {
+		return g.client
+	}
+
+	tc := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	client := github.NewClient(tc)
+	client.BaseURL = g.client.BaseURL
+	client.UploadURL = g.client.UploadURL
+
+	return client
+}
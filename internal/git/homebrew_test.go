@@ -0,0 +1,157 @@
+package git
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTap struct {
+	files    map[string]string
+	branches map[string]bool
+	commits  []map[string]any
+	prs      []map[string]any
+}
+
+func (f *fakeTap) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/homebrew-tap":
+			_, _ = w.Write([]byte(`{"default_branch":"main"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/homebrew-tap/contents/Formula/acme.rb":
+			content, ok := f.files[r.URL.Query().Get("ref")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"type":     "file",
+				"encoding": "base64",
+				"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+				"sha":      "blob-" + r.URL.Query().Get("ref"),
+			})
+		case r.Method == http.MethodPut && r.URL.Path == "/repos/acme/homebrew-tap/contents/Formula/acme.rb":
+			var body map[string]any
+			data, _ := io.ReadAll(r.Body)
+			require.NoError(t, json.Unmarshal(data, &body))
+			f.commits = append(f.commits, body)
+			_, _ = w.Write([]byte(`{"commit":{"html_url":"https://github.com/acme/homebrew-tap/commit/abc"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/homebrew-tap/git/ref/heads/main":
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"base-sha"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/homebrew-tap/git/ref/heads/speakeasy-homebrew-acme-v1.2.3":
+			if !f.branches["speakeasy-homebrew-acme-v1.2.3"] {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/speakeasy-homebrew-acme-v1.2.3","object":{"sha":"branch-sha"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/homebrew-tap/git/refs":
+			f.branches["speakeasy-homebrew-acme-v1.2.3"] = true
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/homebrew-tap/pulls":
+			_ = json.NewEncoder(w).Encode(f.prs)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/homebrew-tap/pulls":
+			pr := map[string]any{"html_url": "https://github.com/acme/homebrew-tap/pull/1"}
+			f.prs = append(f.prs, pr)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(pr)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestCommitHomebrewFormula_Direct(t *testing.T) {
+	tap := &fakeTap{files: map[string]string{"main": "old"}, branches: map[string]bool{}}
+	g := &Git{client: newTestGitHubClient(t, tap.handler(t))}
+
+	url, err := g.CommitHomebrewFormula("acme/homebrew-tap", "Formula/acme.rb", "new", "acme", "1.2.3", false)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/homebrew-tap/commit/abc", url)
+
+	require.Len(t, tap.commits, 1)
+	assert.Equal(t, "main", tap.commits[0]["branch"])
+	assert.Equal(t, "blob-main", tap.commits[0]["sha"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("new")), tap.commits[0]["content"])
+	assert.Empty(t, tap.prs)
+}
+
+func TestCommitHomebrewFormula_UpToDate(t *testing.T) {
+	tap := &fakeTap{files: map[string]string{"main": "new"}, branches: map[string]bool{}}
+	g := &Git{client: newTestGitHubClient(t, tap.handler(t))}
+
+	url, err := g.CommitHomebrewFormula("acme/homebrew-tap", "Formula/acme.rb", "new", "acme", "1.2.3", true)
+	require.NoError(t, err)
+	assert.Empty(t, url)
+	assert.Empty(t, tap.commits)
+}
+
+func TestCommitHomebrewFormula_PR(t *testing.T) {
+	tap := &fakeTap{files: map[string]string{}, branches: map[string]bool{}}
+	g := &Git{client: newTestGitHubClient(t, tap.handler(t))}
+
+	url, err := g.CommitHomebrewFormula("acme/homebrew-tap", "Formula/acme.rb", "new", "acme", "1.2.3", true)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/homebrew-tap/pull/1", url)
+
+	require.Len(t, tap.commits, 1)
+	assert.Equal(t, "speakeasy-homebrew-acme-v1.2.3", tap.commits[0]["branch"])
+	assert.Nil(t, tap.commits[0]["sha"])
+	require.Len(t, tap.prs, 1)
+
+	// A rerun reuses the branch and PR
+	tap.files["speakeasy-homebrew-acme-v1.2.3"] = "new"
+	url, err = g.CommitHomebrewFormula("acme/homebrew-tap", "Formula/acme.rb", "new", "acme", "1.2.3", true)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/homebrew-tap/pull/1", url)
+	assert.Len(t, tap.commits, 1)
+	assert.Len(t, tap.prs, 1)
+}
+
+func TestCommitHomebrewFormula_InvalidTap(t *testing.T) {
+	g := &Git{}
+
+	_, err := g.CommitHomebrewFormula("acme", "Formula/acme.rb", "new", "acme", "1.2.3", false)
+	assert.ErrorContains(t, err, "invalid homebrew tap")
+}
+
+func TestGetReleaseAssets_Paginates(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "acme")
+	t.Setenv("GITHUB_REPOSITORY", "acme/cli")
+
+	g := &Git{client: newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/repos/acme/cli/releases/tags/v1.2.3":
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.2.3","assets":[{"id":1,"name":"acme_darwin_arm64.tar.gz"}]}`))
+		case r.URL.Path == "/repos/acme/cli/releases/7/assets" && r.URL.Query().Get("page") == "":
+			w.Header().Set("Link", `<`+"http://"+r.Host+`/repos/acme/cli/releases/7/assets?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":1,"name":"acme_darwin_arm64.tar.gz"}]`))
+		case r.URL.Path == "/repos/acme/cli/releases/7/assets" && r.URL.Query().Get("page") == "2":
+			_, _ = w.Write([]byte(`[{"id":2,"name":"checksums.txt"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})}
+
+	assets, err := g.GetReleaseAssets("v1.2.3")
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		names = append(names, asset.GetName())
+	}
+	assert.Equal(t, []string{"acme_darwin_arm64.tar.gz", "checksums.txt"}, names)
+}
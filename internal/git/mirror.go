@@ -0,0 +1,90 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+)
+
+const defaultMirrorBranch = "main"
+
+// MirrorTarget splits the history of the target output directory dir up to
+// commitHash into its own history, pushes it to the target's mirror repository
+// and tags it with the release version. It returns the URL of the tag on the
+// mirror, or an empty string if the target isn't mirrored.
+func (g *Git) MirrorTarget(dir, version, commitHash string) (string, error) {
+	dir = filepath.Clean(dir)
+	mirror := environment.GetMirrorRepository(dir)
+	if mirror == "" {
+		return "", nil
+	}
+	if dir == "." {
+		return "", fmt.Errorf("cannot mirror the root of the repository to %s", mirror)
+	}
+
+	remote, err := mirrorRemoteURL(mirror, environment.GetMirrorAccessToken())
+	if err != nil {
+		return "", err
+	}
+
+	logging.Info("Splitting history of %s for mirror %s", dir, mirror)
+
+	out, err := runGitCommand(g.context(), "subtree", "split", "--prefix="+filepath.ToSlash(dir), commitHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to split history of %s: %w", dir, err)
+	}
+	splitHash := strings.TrimSpace(out)
+	if splitHash == "" {
+		return "", fmt.Errorf("failed to split history of %s: no commits found", dir)
+	}
+
+	branch := mirrorBranch()
+	tag := "v" + version
+
+	// The mirror only ever contains the split history, so the branch is force pushed in case the split changed
+	if _, err := runGitCommand(g.context(), "push", "--force", remote, fmt.Sprintf("%s:refs/heads/%s", splitHash, branch)); err != nil {
+		return "", redact.Error(fmt.Errorf("failed to push %s to mirror %s: %w", dir, mirror, err))
+	}
+
+	// Tags are never force pushed, pushing the same commit to an existing tag is a no-op
+	if _, err := runGitCommand(g.context(), "push", remote, fmt.Sprintf("%s:refs/tags/%s", splitHash, tag)); err != nil {
+		return "", redact.Error(fmt.Errorf("failed to push tag %s to mirror %s: %w", tag, mirror, err))
+	}
+
+	logging.Info("Mirrored %s to %s@%s as %s", dir, mirror, branch, tag)
+
+	return fmt.Sprintf("%s/%s/releases/tag/%s", environment.GetGithubServerURL(), mirror, tag), nil
+}
+
+func mirrorRemoteURL(mirror, accessToken string) (string, error) {
+	serverURL := environment.GetGithubServerURL()
+	if serverURL == "" {
+		serverURL = "https://github.com"
+	}
+
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid github server url %s: %w", serverURL, err)
+	}
+
+	if accessToken != "" {
+		u.User = url.UserPassword("gen", accessToken)
+	}
+	u.Path = path.Join("/", u.Path, strings.Trim(mirror, "/")) + ".git"
+
+	return u.String(), nil
+}
+
+func mirrorBranch() string {
+	if branch := strings.TrimPrefix(environment.GetRef(), "refs/heads/"); branch != "" && !strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+
+	return defaultMirrorBranch
+}
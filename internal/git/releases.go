@@ -3,16 +3,23 @@ package git
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/google/go-github/v63/github"
 	config "github.com/speakeasy-api/sdk-gen-config"
+	"github.com/speakeasy-api/sdk-generation-action/internal/artifacts"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 	"github.com/speakeasy-api/sdk-generation-action/internal/telemetry"
 	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
 	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
@@ -23,169 +30,684 @@ var tfGoReleaserConfig string
 
 const PublishingCompletedString = "Publishing Completed"
 
-func (g *Git) SetReleaseToPublished(version, directory string) error {
+// publishingMarker prefixes the hidden comment recording, for draft releases,
+// the publishing result of each registry the release is published to.
+const publishingMarker = "<!-- speakeasy-publishing "
+
+var publishingMarkerRegex = regexp.MustCompile(`(?s)\n*<!-- speakeasy-publishing (\{.*?\}) -->.*$`)
+
+type publishingState struct {
+	// Registries maps each registry to pending, success or failed
+	Registries map[string]string `json:"registries"`
+}
+
+const (
+	publishingPending = "pending"
+	publishingSuccess = "success"
+	publishingFailed  = "failed"
+)
+
+// RecordPublishingResult records the result of publishing the release with the
+// tag to a registry.
+//
+// Draft releases are published, and made the latest release unless they are a
+// prerelease, once every registry they are published to has succeeded. On
+// failure the draft is annotated with the failure and left unpublished. Other
+// releases have "Publishing Completed" added on success.
+func (g *Git) RecordPublishingResult(tag, registry string, success bool, runURL string) error {
 	if g.repo == nil {
 		return fmt.Errorf("repo not cloned")
 	}
-	tag := "v" + version
-	if directory != "" && directory != "." && directory != "./" {
-		tag = fmt.Sprintf("%s/%s", directory, tag)
-	}
 
-	release, _, err := g.client.Repositories.GetReleaseByTag(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), tag)
+	release, err := g.getReleaseByTag(tag)
 	if err != nil {
-		return fmt.Errorf("failed to get release for tag %s: %w", tag, err)
+		return err
+	}
+	if release == nil {
+		return fmt.Errorf("failed to get release for tag %s: release not found", tag)
 	}
 
-	if release != nil && release.ID != nil {
-		if release.Body != nil && !strings.Contains(*release.Body, PublishingCompletedString) {
-			body := *release.Body + "\n\n" + PublishingCompletedString
-			release.Body = &body
+	base, state, tracked := parsePublishingState(release.GetBody())
+	if !release.GetDraft() && !tracked {
+		if !success {
+			return nil
+		}
+		if !strings.Contains(base, PublishingCompletedString) {
+			release.Body = github.String(base + "\n\n" + PublishingCompletedString)
+		}
+	} else {
+		if registry == "" {
+			registry = "unknown"
+		}
+		state.Registries[registry] = publishingFailed
+		if success {
+			state.Registries[registry] = publishingSuccess
 		}
 
-		if _, _, err = g.client.Repositories.EditRelease(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), *release.ID, release); err != nil {
-			return fmt.Errorf("failed to add to release body for tag %s: %w", tag, err)
+		if state.allSucceeded() {
+			logging.Info("Publishing completed for all registries, publishing release %s", tag)
+			release.Body = github.String(base + "\n\n" + PublishingCompletedString)
+			release.Draft = github.Bool(false)
+			release.MakeLatest = github.String("true")
+			if release.GetPrerelease() {
+				release.MakeLatest = github.String("false")
+			}
+		} else {
+			release.Body = github.String(base + renderPublishingState(state, runURL))
 		}
 	}
 
+	if _, _, err = g.client.Repositories.EditRelease(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), release.GetID(), release); err != nil {
+		return fmt.Errorf("failed to update release for tag %s: %w", tag, err)
+	}
+
 	return nil
 }
 
-func (g *Git) CreateRelease(oldReleaseContent string, languages map[string]releases.LanguageReleaseInfo, outputs map[string]string, targetSpecificReleaseNotes releases.TargetReleaseNotes) error {
+func (s publishingState) allSucceeded() bool {
+	for _, status := range s.Registries {
+		if status != publishingSuccess {
+			return false
+		}
+	}
+
+	return len(s.Registries) > 0
+}
+
+// parsePublishingState splits a release body into its content and the
+// publishing state recorded in it, if any.
+func parsePublishingState(body string) (string, publishingState, bool) {
+	state := publishingState{Registries: map[string]string{}}
+
+	match := publishingMarkerRegex.FindStringSubmatchIndex(body)
+	if match == nil {
+		return body, state, false
+	}
+
+	if err := json.Unmarshal([]byte(body[match[2]:match[3]]), &state); err != nil {
+		logging.Info("Failed to parse publishing state in release body: %v", err)
+	}
+	if state.Registries == nil {
+		state.Registries = map[string]string{}
+	}
+
+	return body[:match[0]], state, true
+}
+
+func renderPublishingState(state publishingState, runURL string) string {
+	data, _ := json.Marshal(state)
+
+	var sb strings.Builder
+	sb.WriteString("\n\n")
+	sb.WriteString(publishingMarker)
+	sb.Write(data)
+	sb.WriteString(" -->")
+
+	registries := make([]string, 0, len(state.Registries))
+	for registry := range state.Registries {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	for _, registry := range registries {
+		if state.Registries[registry] != publishingFailed {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n> [!WARNING]\n> Publishing to %s failed", registry)
+		if runURL != "" {
+			fmt.Fprintf(&sb, ", see %s", runURL)
+		}
+		sb.WriteString(". This release will be published once publishing succeeds.\n")
+	}
+
+	return sb.String()
+}
+
+type ReleaseStatus string
+
+const (
+	// ReleaseStatusCreated means the tag and release were created by this run.
+	ReleaseStatusCreated ReleaseStatus = "created"
+	// ReleaseStatusUpdated means the tag or release already existed, for example
+	// from an earlier partially failed run, and was brought up to date.
+	ReleaseStatusUpdated ReleaseStatus = "updated"
+	// ReleaseStatusPublished means the release already existed and has been
+	// published, so it was left as is and publishing is skipped.
+	ReleaseStatusPublished ReleaseStatus = "already_published"
+	ReleaseStatusFailed    ReleaseStatus = "failed"
+)
+
+// ReleaseResult records the outcome of creating the release for a target.
+type ReleaseResult struct {
+	Target string        `json:"target"`
+	Tag    string        `json:"tag"`
+	Status ReleaseStatus `json:"status"`
+	URL    string        `json:"url,omitempty"`
+	Draft  bool          `json:"draft,omitempty"`
+	// MirrorURL is the URL of the release tag on the target's mirror repository, if it has one.
+	MirrorURL string `json:"mirror_url,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// CreateRelease creates the tag and release for every language, in order. It
+// is idempotent per language: tags and releases left behind by an earlier run
+// are reconciled rather than recreated, and a failure for one language doesn't
+// prevent the others from being released, so rerunning completes exactly the
+// missing pieces. The per-language results are returned, along with an error
+// joining every failure, and set as outputs.
+func (g *Git) CreateRelease(oldReleaseContent string, languages map[string]releases.LanguageReleaseInfo, outputs map[string]string, targetSpecificReleaseNotes releases.TargetReleaseNotes) ([]ReleaseResult, error) {
 	if g.repo == nil {
-		return fmt.Errorf("repo not cloned")
+		return nil, fmt.Errorf("repo not cloned")
 	}
 
-	fmt.Println("Creating release")
+	logging.Info("Creating release")
 
 	headRef, err := g.repo.Head()
 	if err != nil {
-		return fmt.Errorf("failed to get head ref: %w", err)
+		return nil, fmt.Errorf("failed to get head ref: %w", err)
 	}
 
 	commitHash := headRef.Hash().String()
 
-	for lang, info := range languages {
-		tag := "v" + info.Version
-		if info.Path != "" && info.Path != "." && info.Path != "./" {
-			tag = fmt.Sprintf("%s/%s", info.Path, tag)
+	langs := make([]string, 0, len(languages))
+	for lang := range languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	results := make([]ReleaseResult, 0, len(langs))
+	var errs []error
+	for _, lang := range langs {
+		info := languages[lang]
+
+		endGroup := logging.Group("Release %s", lang)
+		result, err := g.createTargetRelease(lang, info, commitHash, oldReleaseContent, outputs, targetSpecificReleaseNotes)
+		if err != nil {
+			result.Status = ReleaseStatusFailed
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", lang, err))
+		} else if mirrorURL, err := g.MirrorTarget(info.Path, info.Version, commitHash); err != nil {
+			result.Status = ReleaseStatusFailed
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", lang, err))
+		} else if mirrorURL != "" {
+			result.MirrorURL = mirrorURL
+			outputs[utils.OutputTargetMirrorURL(lang)] = mirrorURL
 		}
 
-		if lang == "terraform" {
-			// Terraform is a special case -- we use go releaser externally to turn this tag into a release.
-			err = g.CreateTag("v"+info.Version, commitHash)
-			if err != nil {
-				return fmt.Errorf("failed to create tag: %w", err)
-			}
-			// Copy our standard terraform config into /tmp/.goreleaser.yml
-			err = os.WriteFile("/tmp/.goreleaser.yml", []byte(tfGoReleaserConfig), 0644)
-			if err != nil {
-				return fmt.Errorf("failed to write goreleaser config: %w", err)
-			}
-			cmd := exec.Command("goreleaser", "release", "--clean", "--config", "/tmp/.goreleaser.yml")
-			cmd.Dir = filepath.Join(environment.GetWorkspace(), "repo")
-			cmd.Env = append(os.Environ(),
-				"GORELEASER_PREVIOUS_TAG="+info.PreviousVersion,
-				"GORELEASER_CURRENT_TAG="+tag,
-				"GITHUB_TOKEN="+environment.GetAccessToken(),
-				"GPG_FINGERPRINT="+environment.GetGPGFingerprint(),
-			)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			if err := cmd.Run(); err != nil {
-				return fmt.Errorf("failed to run goreleaser: %w", err)
-			}
-		} else if lang == "cli" {
-			// CLI uses the publish-cli workflow job's GoReleaser invocation to create the release.
-			goreleaserTag := "v" + info.Version
-			err = g.CreateTag(goreleaserTag, commitHash)
-			if err != nil {
-				return fmt.Errorf("failed to create tag: %w", err)
+		endGroup()
+
+		outputs[utils.OutputTargetReleaseStatus(lang)] = string(result.Status)
+		results = append(results, result)
+	}
+
+	printReleaseResults(results)
+	for _, result := range results {
+		summary.AddRelease(summary.Release{
+			Target: result.Target,
+			Tag:    result.Tag,
+			Status: string(result.Status),
+			URL:    result.URL,
+			Error:  result.Error,
+		})
+	}
+
+	if data, err := json.Marshal(results); err == nil {
+		outputs["release_results"] = string(data)
+	}
+
+	return results, errors.Join(errs...)
+}
+
+func (g *Git) createTargetRelease(lang string, info releases.LanguageReleaseInfo, commitHash, oldReleaseContent string, outputs map[string]string, targetSpecificReleaseNotes releases.TargetReleaseNotes) (ReleaseResult, error) {
+	tag := ReleaseTag(lang, info.Path, info.Version)
+
+	result := ReleaseResult{Target: lang, Tag: tag}
+
+	if lang == "go" {
+		goRelease, err := releases.ValidateGoRelease(filepath.Join(environment.GetWorkspace(), "repo"), info.Path, info.Version)
+		if err != nil {
+			return result, fmt.Errorf("invalid go release: %w", err)
+		}
+		tag = goRelease.Tag
+		result.Tag = tag
+	}
+
+	if lang == "swift" {
+		if err := validateSwiftRelease(info); err != nil {
+			return result, err
+		}
+	}
+
+	if lang == "terraform" {
+		// Terraform is a special case -- we use go releaser externally to turn this tag into a release.
+		result.Tag = "v" + info.Version
+		result.URL = tagURL(result.Tag)
+		onRemote, err := g.ensureTag(result.Tag, commitHash)
+		if err != nil {
+			return result, err
+		}
+		if !onRemote && TagSigningEnabled() {
+			// Push the signed tag so goreleaser doesn't create an unsigned one with the release
+			if err := g.PushTag(result.Tag); err != nil {
+				return result, fmt.Errorf("failed to push tag: %w", err)
 			}
+		}
+
+		existing, err := g.getReleaseByTag(result.Tag)
+		if err != nil {
+			return result, err
+		}
+		if existing != nil {
+			logging.Info("A github release with tag %s already exists ... skipping goreleaser", result.Tag)
+			result.Status = ReleaseStatusUpdated
+			result.URL = existing.GetHTMLURL()
+			return result, nil
+		}
+
+		// Copy our standard terraform config into /tmp/.goreleaser.yml
+		if err := os.WriteFile("/tmp/.goreleaser.yml", []byte(tfGoReleaserConfig), 0644); err != nil {
+			return result, fmt.Errorf("failed to write goreleaser config: %w", err)
+		}
+		cmd := process.Command(g.context(), process.PhaseRelease, "goreleaser", "release", "--clean", "--config", "/tmp/.goreleaser.yml")
+		cmd.Dir = filepath.Join(environment.GetWorkspace(), "repo")
+		cmd.Env = append(os.Environ(),
+			"GORELEASER_PREVIOUS_TAG="+info.PreviousVersion,
+			"GORELEASER_CURRENT_TAG="+tag,
+			"GITHUB_TOKEN="+environment.GetAccessToken(),
+			"GPG_FINGERPRINT="+environment.GetGPGFingerprint(),
+		)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return result, fmt.Errorf("failed to run goreleaser: %w", err)
+		}
+
+		result.Status = ReleaseStatusCreated
+		return result, nil
+	}
+
+	if lang == "cli" {
+		// CLI uses the publish-cli workflow job's GoReleaser invocation to create the release.
+		goreleaserTag := "v" + info.Version
+		result.Tag = goreleaserTag
+		result.URL = tagURL(goreleaserTag)
+
+		pushed, err := g.ensureTag(goreleaserTag, commitHash)
+		if err != nil {
+			return result, err
+		}
+		result.Status = ReleaseStatusUpdated
+		if !pushed {
 			if err := g.PushTag(goreleaserTag); err != nil {
-				return fmt.Errorf("failed to push tag: %w", err)
-			}
-			outputs[utils.OutputTargetGoReleaserCurrentTag(lang)] = goreleaserTag
-			if info.PreviousVersion != "" {
-				outputs[utils.OutputTargetGoReleaserPreviousTag(lang)] = "v" + info.PreviousVersion
-			}
-		} else {
-			tagName := github.String(tag)
-			releaseBody := oldReleaseContent
-			logging.Info("INPUT_ENABLE_SDK_CHANGELOG: %s", environment.GetSDKChangelog())
-			logging.Info("targetSpecificReleaseNotes: %v", targetSpecificReleaseNotes)
-			logging.Info("targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang): %v", targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang))
-			if environment.GetSDKChangelog() == "true" && targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang) {
-				releaseBody = targetSpecificReleaseNotes.GetReleaseNotesForTarget(lang)
-				fmt.Println(fmt.Sprintf("Release Notes Body: \n%s\n", releaseBody))
+				return result, fmt.Errorf("failed to push tag: %w", err)
 			}
+			result.Status = ReleaseStatusCreated
+		}
+
+		outputs[utils.OutputTargetGoReleaserCurrentTag(lang)] = goreleaserTag
+		if info.PreviousVersion != "" {
+			outputs[utils.OutputTargetGoReleaserPreviousTag(lang)] = "v" + info.PreviousVersion
+		}
+		return result, nil
+	}
+
+	releaseBody := oldReleaseContent
+	logging.Info("INPUT_ENABLE_SDK_CHANGELOG: %s", environment.GetSDKChangelog())
+	logging.Info("targetSpecificReleaseNotes: %v", targetSpecificReleaseNotes)
+	logging.Info("targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang): %v", targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang))
+	if environment.GetSDKChangelog() == "true" && targetSpecificReleaseNotes.HasReleaseNotesForTarget(lang) {
+		releaseBody = targetSpecificReleaseNotes.GetReleaseNotesForTarget(lang)
+		logging.Info("Release Notes Body: \n%s", releaseBody)
+	}
+
+	release := &github.RepositoryRelease{
+		TagName:         github.String(tag),
+		TargetCommitish: github.String(commitHash),
+		Name:            github.String(fmt.Sprintf("%s - %s - %s", lang, tag, environment.GetInvokeTime().Format("2006-01-02 15:04:05"))),
+		Body:            github.String(fmt.Sprintf(`# Generated by Speakeasy CLI%s`, releaseBody)),
+	}
+	if info.IsPrerelease() {
+		release.Prerelease = github.Bool(true)
+		// prereleases shouldn’t become the “latest”:
+		release.MakeLatest = github.String("false")
+	}
+
+	existing, err := g.getReleaseByTag(tag)
+	if err != nil {
+		return result, err
+	}
 
-			release := &github.RepositoryRelease{
-				TagName:         tagName,
-				TargetCommitish: github.String(commitHash),
-				Name:            github.String(fmt.Sprintf("%s - %s - %s", lang, tag, environment.GetInvokeTime().Format("2006-01-02 15:04:05"))),
-				Body:            github.String(fmt.Sprintf(`# Generated by Speakeasy CLI%s`, releaseBody)),
+	if g.shouldDraftRelease(lang, info, outputs) && (existing == nil || existing.GetDraft()) {
+		// The release is published by publish-event once every registry reports success
+		_, state, _ := parsePublishingState(existing.GetBody())
+		for _, registry := range draftRegistries(lang) {
+			if _, ok := state.Registries[registry]; !ok {
+				state.Registries[registry] = publishingPending
 			}
-			if info.IsPrerelease() {
-				release.Prerelease = github.Bool(true)
-				// prereleases shouldn’t become the “latest”:
-				release.MakeLatest = github.String("false")
+		}
+		release.Draft = github.Bool(true)
+		release.MakeLatest = nil
+		release.Body = github.String(release.GetBody() + renderPublishingState(state, ""))
+		result.Draft = true
+	}
+
+	if existing != nil {
+		result.URL = existing.GetHTMLURL()
+		if strings.Contains(existing.GetBody(), PublishingCompletedString) {
+			logging.Info("a github release with tag %s has already been published ... skipping publishing", tag)
+			logging.Info("to publish this version again please check with your package managed delete the github tag and release")
+			outputName := utils.OutputTargetPublish(lang)
+			if _, ok := outputs[outputName]; ok {
+				outputs[outputName] = "false"
 			}
-			_, _, err = g.client.Repositories.CreateRelease(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), release)
+			result.Status = ReleaseStatusPublished
+			return result, nil
+		}
 
+		// The release was created by an earlier run that didn't complete, so bring it up to date. Its tag is
+		// kept, as it may already have been consumed by a publishing job.
+		logging.Info("A github release with tag %s already exists ... updating it", tag)
+		release.TagName = nil
+		release.TargetCommitish = nil
+		existing, _, err = g.client.Repositories.EditRelease(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), existing.GetID(), release)
+		if err != nil {
+			return result, fmt.Errorf("failed to update release for tag %s: %w", tag, err)
+		}
+		result.Status = ReleaseStatusUpdated
+	} else {
+		if TagSigningEnabled() {
+			// Create and push the signed tag up front, as GitHub would otherwise create an unsigned tag for the release
+			onRemote, err := g.ensureTag(tag, commitHash)
 			if err != nil {
-				if release, _, err := g.client.Repositories.GetReleaseByTag(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), *tagName); err == nil && release != nil {
-					if release.Body != nil && strings.Contains(*release.Body, PublishingCompletedString) {
-						fmt.Println(fmt.Sprintf("a github release with tag %s has already been published ... skipping publishing", *tagName))
-						fmt.Println(fmt.Sprintf("to publish this version again please check with your package managed delete the github tag and release"))
-						outputName := utils.OutputTargetPublish(lang)
-						if _, ok := outputs[outputName]; ok {
-							outputs[outputName] = "false"
-						}
-					}
-					// TODO: Consider deleting and recreating the release if we are moving forward with publishing
-					return nil
-				}
-				// If the release fails, trigger a failed publishing CLI event
-				if _, publishEventErr := telemetry.TriggerPublishingEvent(info.Path, "failed", utils.GetRegistryName(lang)); publishEventErr != nil {
-					fmt.Printf("failed to write publishing event: %v\n", publishEventErr)
+				return result, err
+			}
+			if !onRemote {
+				if err := g.PushTag(tag); err != nil {
+					return result, fmt.Errorf("failed to push tag: %w", err)
 				}
+			}
+		} else if tagCommit, found, err := g.lookupTagCommit(tag); err != nil {
+			return result, err
+		} else if found && tagCommit != commitHash {
+			logging.Info("Tag %s already exists at commit %s ... creating the release from the existing tag", tag, tagCommit)
+		}
 
-				return fmt.Errorf("failed to create release for tag %s: %w", *tagName, err)
+		existing, _, err = g.client.Repositories.CreateRelease(context.Background(), os.Getenv("GITHUB_REPOSITORY_OWNER"), GetRepo(), release)
+		if err != nil {
+			// If the release fails, trigger a failed publishing CLI event
+			if _, _, publishEventErr := telemetry.TriggerPublishingEvent(info.Path, "failed", utils.GetRegistryName(lang), lang); publishEventErr != nil {
+				logging.Info("failed to write publishing event: %v", publishEventErr)
 			}
 
-			switch lang {
-			case "go":
-				// Go has no publishing job, so we publish a CLI event on github release here
-				if _, publishEventErr := telemetry.TriggerPublishingEvent(info.Path, "success", utils.GetRegistryName(lang)); publishEventErr != nil {
-					fmt.Printf("failed to write publishing event: %v\n", publishEventErr)
-				}
-			case "mcp-typescript":
-				// This target should always upload the MCP binaries to the release
-				outputs[utils.OutputTargetMCPRelease(lang)] = *tagName
-			case "typescript":
-				if err := g.AttachMCPReleaseTag(info.Path, *tagName, outputs); err != nil {
-					fmt.Printf("attempted to tag standalone MCP binary: %v\n", err)
-				}
+			return result, fmt.Errorf("failed to create release for tag %s: %w", tag, err)
+		}
+		result.Status = ReleaseStatusCreated
+
+		if lang == "go" || lang == "swift" {
+			// Go and Swift have no publishing job, so we publish a CLI event on github release here
+			if _, _, publishEventErr := telemetry.TriggerPublishingEvent(info.Path, "success", utils.GetRegistryName(lang), lang); publishEventErr != nil {
+				logging.Info("failed to write publishing event: %v", publishEventErr)
 			}
 		}
 	}
+	result.URL = existing.GetHTMLURL()
+	result.Draft = existing.GetDraft()
+
+	switch lang {
+	case "mcp-typescript":
+		// This target should always upload the MCP binaries to the release
+		outputs[utils.OutputTargetMCPRelease(lang)] = tag
+	case "typescript":
+		if err := g.AttachMCPReleaseTag(info.Path, tag, outputs); err != nil {
+			logging.Info("attempted to tag standalone MCP binary: %v", err)
+		}
+	}
+
+	if environment.AttachReleaseArtifacts() {
+		// Artifacts are a convenience on top of the registry publish, so failures shouldn't fail the release
+		if err := g.attachReleaseArtifacts(lang, info, existing, outputs); err != nil {
+			logging.With("target", lang).Info("failed to attach release artifacts: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// validateSwiftRelease checks the Swift target can be resolved by Swift Package
+// Manager, which only supports packages with a Package.swift at the root of a
+// repository tagged with a semantic version.
+func validateSwiftRelease(info releases.LanguageReleaseInfo) error {
+	if _, err := os.Stat(filepath.Join(environment.GetWorkspace(), "repo", info.Path, "Package.swift")); err != nil {
+		return fmt.Errorf("no Package.swift found for swift target in %s: %w", info.Path, err)
+	}
+
+	dir := filepath.Clean(info.Path)
+	if dir != "." && environment.GetMirrorRepository(dir) == "" {
+		logging.Annotation{Title: "Swift package not published", File: filepath.ToSlash(filepath.Join(dir, "Package.swift"))}.
+			Warning("Swift Package Manager can't resolve the swift target in %s from a subdirectory, configure mirror_repositories to publish it to its own repository", dir)
+	}
 
 	return nil
 }
 
+// shouldDraftRelease returns true if draft releases are enabled and the release
+// will be published by a publishing job that reports back via publish-event.
+// Go and Swift have no publishing job, and MCP binaries are uploaded to the
+// release by tag, which isn't possible while it is a draft.
+func (g *Git) shouldDraftRelease(lang string, info releases.LanguageReleaseInfo, outputs map[string]string) bool {
+	if !environment.DraftReleases() || lang == "go" || lang == "swift" || lang == "mcp-typescript" {
+		return false
+	}
+	if outputs[utils.OutputTargetPublish(lang)] != "true" {
+		return false
+	}
+	if lang == "typescript" {
+		if enabled, err := mcpServerEnabled(info.Path); err != nil || enabled {
+			return false
+		}
+	}
+
+	return true
+}
+
+// draftRegistries returns the registries, as reported by publish-event, that
+// must succeed before the draft release for the language is published.
+func draftRegistries(lang string) []string {
+	return []string{publishtargets.ForTarget(lang).EventName}
+}
+
+// ensureTag creates the tag at the given commit if it doesn't already exist
+// locally, and returns whether it already exists on the remote. An existing tag
+// is kept even if it points to a different commit, as it may already have been
+// released.
+func (g *Git) ensureTag(tag, commitHash string) (bool, error) {
+	remoteCommit, onRemote, err := g.lookupTagCommit(tag)
+	if err != nil {
+		return false, err
+	}
+	if onRemote && remoteCommit != commitHash {
+		logging.Info("Tag %s already exists at commit %s ... keeping the existing tag", tag, remoteCommit)
+	}
+
+	if _, err := g.repo.Tag(tag); err == nil {
+		logging.Info("Tag %s already exists locally", tag)
+		return onRemote, nil
+	}
+
+	hash := commitHash
+	if onRemote {
+		hash = remoteCommit
+	}
+	if err := g.CreateTag(tag, hash); err != nil {
+		return false, fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return onRemote, nil
+}
+
+// getReleaseByTag returns the release for the tag, or nil if there isn't one.
+// Draft releases aren't returned when getting a release by tag, so they are
+// looked up from the list of releases instead.
+func (g *Git) getReleaseByTag(tag string) (*github.RepositoryRelease, error) {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+
+	release, res, err := g.client.Repositories.GetReleaseByTag(context.Background(), owner, GetRepo(), tag)
+	if err == nil {
+		return release, nil
+	}
+	if !isNotFound(res) {
+		return nil, fmt.Errorf("failed to get release for tag %s: %w", tag, err)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, res, err := g.client.Repositories.ListReleases(context.Background(), owner, GetRepo(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		for _, release := range releases {
+			if release.GetDraft() && release.GetTagName() == tag {
+				return release, nil
+			}
+		}
+		if res == nil || res.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+func tagURL(tag string) string {
+	return fmt.Sprintf("https://github.com/%s/releases/tag/%s", os.Getenv("GITHUB_REPOSITORY"), tag)
+}
+
+func printReleaseResults(results []ReleaseResult) {
+	var sb strings.Builder
+	sb.WriteString("Release results:\n")
+	for _, result := range results {
+		fmt.Fprintf(&sb, "  %-16s %-32s %-18s %s", result.Target, result.Tag, result.Status, result.URL)
+		if result.Error != "" {
+			fmt.Fprintf(&sb, " (%s)", result.Error)
+		}
+		sb.WriteString("\n")
+	}
+
+	logging.Info("%s", sb.String())
+}
+
 func (g *Git) AttachMCPReleaseTag(path, tagName string, outputs map[string]string) error {
-	loadedCfg, err := config.Load(filepath.Join(environment.GetWorkspace(), "repo", path))
+	enabled, err := mcpServerEnabled(path)
 	if err != nil {
 		return err
 	}
+	if enabled {
+		outputs[utils.OutputTargetMCPRelease("typescript")] = tagName
+		return nil
+	}
+
+	logging.Info("No MCP server present ... skipping MCP binary tagging")
+	return nil
+}
+
+func mcpServerEnabled(path string) (bool, error) {
+	loadedCfg, err := config.Load(filepath.Join(environment.GetWorkspace(), "repo", path))
+	if err != nil {
+		return false, err
+	}
 	if tsConfig, ok := loadedCfg.Config.Languages["typescript"]; ok {
 		if enable, ok := tsConfig.Cfg["enableMCPServer"].(bool); ok && enable {
-			outputs[utils.OutputTargetMCPRelease("typescript")] = tagName
-			return nil
+			return true, nil
 		}
 	}
 
-	fmt.Println("No MCP server present ... skipping MCP binary tagging")
+	return false, nil
+}
+
+// UploadReleaseAssets uploads the given files to the release and returns their
+// download URLs. Assets left behind by a previous attempt with the same name are
+// replaced, so reruns always publish artifacts matching the SHA256SUMS manifest.
+func (g *Git) UploadReleaseAssets(releaseID int64, files []string) ([]string, error) {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+	repo := GetRepo()
+
+	existing := map[string]int64{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		assets, res, err := g.client.Repositories.ListReleaseAssets(context.Background(), owner, repo, releaseID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list release assets: %w", err)
+		}
+		for _, asset := range assets {
+			existing[asset.GetName()] = asset.GetID()
+		}
+		if res == nil || res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+
+	urls := make([]string, 0, len(files))
+	for _, file := range files {
+		name := filepath.Base(file)
+
+		if id, ok := existing[name]; ok {
+			logging.Info("Replacing existing release asset %s", name)
+			if _, err := g.client.Repositories.DeleteReleaseAsset(context.Background(), owner, repo, id); err != nil {
+				return nil, fmt.Errorf("failed to delete existing release asset %s: %w", name, err)
+			}
+		}
+
+		asset, err := g.uploadReleaseAsset(owner, repo, releaseID, file)
+		if err != nil {
+			return nil, err
+		}
+
+		urls = append(urls, asset.GetBrowserDownloadURL())
+	}
+
+	return urls, nil
+}
+
+func (g *Git) uploadReleaseAsset(owner, repo string, releaseID int64, file string) (*github.ReleaseAsset, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open release asset %s: %w", file, err)
+	}
+	defer f.Close()
+
+	asset, _, err := g.client.Repositories.UploadReleaseAsset(context.Background(), owner, repo, releaseID, &github.UploadOptions{Name: filepath.Base(file)}, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload release asset %s: %w", filepath.Base(file), err)
+	}
+
+	return asset, nil
+}
+
+func (g *Git) attachReleaseArtifacts(lang string, info releases.LanguageReleaseInfo, release *github.RepositoryRelease, outputs map[string]string) error {
+	if !artifacts.IsSupported(lang) {
+		logging.Info("Packaging release artifacts is not supported for %s targets ... skipping", lang)
+		return nil
+	}
+
+	dir := filepath.Join(environment.GetWorkspace(), "repo", info.Path)
+	outDir, err := os.MkdirTemp("", "speakeasy-artifacts-"+lang+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	files, err := artifacts.Package(g.context(), lang, dir, outDir)
+	if err != nil {
+		return fmt.Errorf("failed to package %s artifacts: %w", lang, err)
+	}
+
+	urls, err := g.UploadReleaseAssets(release.GetID(), files)
+	if err != nil {
+		return err
+	}
+
+	outputs[utils.OutputTargetReleaseAssets(lang)] = strings.Join(urls, ",")
+
 	return nil
 }
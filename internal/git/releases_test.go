@@ -0,0 +1,211 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadReleaseAssets_ReplacesExistingAssets(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	var deleted []string
+	uploaded := map[string]string{}
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/1/assets":
+			_, err := w.Write([]byte(`[{"id":10,"name":"SHA256SUMS"}]`))
+			require.NoError(t, err)
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/owner/repo/releases/assets/10":
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/releases/1/assets":
+			name := r.URL.Query().Get("name")
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			uploaded[name] = string(body)
+			_, err = w.Write([]byte(fmt.Sprintf(`{"id":11,"name":%q,"browser_download_url":"https://example.com/%s"}`, name, name)))
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	dir := t.TempDir()
+	pkg := filepath.Join(dir, "pkg-1.0.0.tgz")
+	sums := filepath.Join(dir, "SHA256SUMS")
+	require.NoError(t, os.WriteFile(pkg, []byte("package"), 0o644))
+	require.NoError(t, os.WriteFile(sums, []byte("sums"), 0o644))
+
+	g := &Git{client: client}
+	urls, err := g.UploadReleaseAssets(1, []string{pkg, sums})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/pkg-1.0.0.tgz", "https://example.com/SHA256SUMS"}, urls)
+	require.Equal(t, []string{"/repos/owner/repo/releases/assets/10"}, deleted)
+	require.Equal(t, map[string]string{"pkg-1.0.0.tgz": "package", "SHA256SUMS": "sums"}, uploaded)
+}
+
+func TestCreateRelease_ReconcilesEachTarget(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+	t.Setenv("GITHUB_WORKSPACE", t.TempDir())
+
+	var requests []string
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/tags/python/v1.0.0":
+			_, _ = w.Write([]byte(`{"id":1,"body":"# Generated by Speakeasy CLI"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/releases/1":
+			_, _ = w.Write([]byte(`{"id":1,"html_url":"https://example.com/python"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/tags/typescript/v1.0.0":
+			_, _ = w.Write([]byte(`{"id":2,"html_url":"https://example.com/typescript","body":"# Generated by Speakeasy CLI\n\nPublishing Completed"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/releases":
+			var release github.RepositoryRelease
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+			if release.GetTagName() == "ruby/v1.0.0" {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"message":"boom"}`))
+				return
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"id":3,"html_url":"https://example.com/%s"}`, release.GetTagName())))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	})
+
+	repo, _ := newTestRepo(t)
+	g := &Git{repo: repo, client: client}
+
+	languages := map[string]releases.LanguageReleaseInfo{
+		"typescript": {Path: "typescript", Version: "1.0.0"},
+		"ruby":       {Path: "ruby", Version: "1.0.0"},
+		"python":     {Path: "python", Version: "1.0.0"},
+		"csharp":     {Path: "csharp", Version: "1.0.0"},
+	}
+	outputs := map[string]string{"publish_typescript": "true"}
+
+	results, err := g.CreateRelease("", languages, outputs, nil)
+
+	require.ErrorContains(t, err, "ruby: failed to create release for tag ruby/v1.0.0")
+	require.Equal(t, []ReleaseResult{
+		{Target: "csharp", Tag: "csharp/v1.0.0", Status: ReleaseStatusCreated, URL: "https://example.com/csharp/v1.0.0"},
+		{Target: "python", Tag: "python/v1.0.0", Status: ReleaseStatusUpdated, URL: "https://example.com/python"},
+		{Target: "ruby", Tag: "ruby/v1.0.0", Status: ReleaseStatusFailed, Error: results[2].Error},
+		{Target: "typescript", Tag: "typescript/v1.0.0", Status: ReleaseStatusPublished, URL: "https://example.com/typescript"},
+	}, results)
+	require.Equal(t, "false", outputs["publish_typescript"])
+	require.Equal(t, "updated", outputs["python_release_status"])
+	require.Equal(t, "failed", outputs["ruby_release_status"])
+
+	var decoded []ReleaseResult
+	require.NoError(t, json.Unmarshal([]byte(outputs["release_results"]), &decoded))
+	require.Equal(t, results, decoded)
+}
+
+func TestRecordPublishingResult_PublishesDraftOnceEveryRegistrySucceeds(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	release := &github.RepositoryRelease{
+		ID:      github.Int64(7),
+		TagName: github.String("typescript/v1.0.0"),
+		Draft:   github.Bool(true),
+		Body:    github.String("# Generated by Speakeasy CLI" + renderPublishingState(publishingState{Registries: map[string]string{"npm": publishingPending, "jsr": publishingPending}}, "")),
+	}
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/tags/typescript/v1.0.0":
+			// Drafts aren't returned by tag
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases":
+			require.NoError(t, json.NewEncoder(w).Encode([]*github.RepositoryRelease{release}))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/releases/7":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(release))
+			require.NoError(t, json.NewEncoder(w).Encode(release))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	repo, _ := newTestRepo(t)
+	g := &Git{repo: repo, client: client}
+
+	require.NoError(t, g.RecordPublishingResult("typescript/v1.0.0", "npm", false, "https://github.com/owner/repo/actions/runs/1"))
+	require.True(t, release.GetDraft())
+	require.Contains(t, release.GetBody(), "Publishing to npm failed, see https://github.com/owner/repo/actions/runs/1")
+
+	require.NoError(t, g.RecordPublishingResult("typescript/v1.0.0", "npm", true, ""))
+	require.True(t, release.GetDraft())
+	require.NotContains(t, release.GetBody(), "failed")
+	_, state, tracked := parsePublishingState(release.GetBody())
+	require.True(t, tracked)
+	require.Equal(t, map[string]string{"npm": publishingSuccess, "jsr": publishingPending}, state.Registries)
+
+	require.NoError(t, g.RecordPublishingResult("typescript/v1.0.0", "jsr", true, ""))
+	require.False(t, release.GetDraft())
+	require.Equal(t, "true", release.GetMakeLatest())
+	require.Equal(t, "# Generated by Speakeasy CLI\n\n"+PublishingCompletedString, release.GetBody())
+}
+
+func TestRecordPublishingResult_MarksPublishedReleaseCompleted(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	var edited *github.RepositoryRelease
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":7,"body":"# Generated by Speakeasy CLI"}`))
+		case http.MethodPatch:
+			edited = &github.RepositoryRelease{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(edited))
+			_, _ = w.Write([]byte(`{"id":7}`))
+		}
+	})
+
+	repo, _ := newTestRepo(t)
+	g := &Git{repo: repo, client: client}
+
+	require.NoError(t, g.RecordPublishingResult("v1.0.0", "pypi", true, ""))
+	require.Equal(t, "# Generated by Speakeasy CLI\n\n"+PublishingCompletedString, edited.GetBody())
+	require.Nil(t, edited.Draft)
+}
+
+func TestValidateSwiftRelease(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "repo", "swift"), 0o755))
+
+	info := releases.LanguageReleaseInfo{Path: "swift", Version: "1.0.0"}
+	require.ErrorContains(t, validateSwiftRelease(info), "no Package.swift found")
+
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "repo", "swift", "Package.swift"), []byte("// swift-tools-version:5.9"), 0o644))
+	require.NoError(t, validateSwiftRelease(info))
+}
+
+func TestDraftRegistries_RegistryOverride(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_OVERRIDES", `{"typescript": {"name": "artifactory"}}`)
+
+	// Publishing workflows report the registry by its original name
+	require.Equal(t, []string{"npm"}, draftRegistries("typescript"))
+}
@@ -0,0 +1,243 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/google/go-github/v63/github"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+)
+
+const (
+	speakeasyRollbackPRTitle = "chore: 🐝 Rollback SDK - "
+	yankedReleasePrefix      = "[YANKED] "
+)
+
+// ReleaseTag returns the git tag CreateRelease uses for the given language,
+// path and version.
+func ReleaseTag(lang, path, version string) string {
+	return publishtargets.ForTarget(lang).ReleaseTag(releases.PackagePath(lang, path), version)
+}
+
+// ResolveTagCommit returns the hash of the commit the given tag points to,
+// peeling annotated tags.
+func (g *Git) ResolveTagCommit(tag string) (string, error) {
+	hash, found, err := g.lookupTagCommit(tag)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("tag %s not found", tag)
+	}
+
+	return hash, nil
+}
+
+func (g *Git) lookupTagCommit(tag string) (string, bool, error) {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+
+	ref, res, err := g.client.Git.GetRef(context.Background(), owner, GetRepo(), "tags/"+tag)
+	if err != nil {
+		if isNotFound(res) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get tag %s: %w", tag, err)
+	}
+
+	object := ref.GetObject()
+	if object.GetType() == "tag" {
+		annotated, _, err := g.client.Git.GetTag(context.Background(), owner, GetRepo(), object.GetSHA())
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get annotated tag %s: %w", tag, err)
+		}
+		object = annotated.GetObject()
+	}
+
+	if object.GetType() != "commit" {
+		return "", false, fmt.Errorf("tag %s points to a %s, not a commit", tag, object.GetType())
+	}
+
+	return object.GetSHA(), true, nil
+}
+
+// FindRollbackCommit returns the hash of the most recent commit on HEAD whose
+// subject is the given rollback subject, so that a rollback that was already
+// committed to the branch isn't reverted again.
+func (g *Git) FindRollbackCommit(subject string) (string, bool, error) {
+	out, err := runGitCommand(g.context(), "log", "-n", "1", "--format=%H", "--extended-regexp", "--grep=^"+regexp.QuoteMeta(subject)+"$", "HEAD")
+	if err != nil {
+		return "", false, fmt.Errorf("error looking for rollback commit: %w", err)
+	}
+
+	hash := strings.TrimSpace(out)
+	return hash, hash != "", nil
+}
+
+// DeleteReleaseAndTag deletes the GitHub release for the tag, if there is one,
+// and the tag itself. Either already being absent is not an error, so a failed
+// rollback can be retried.
+func (g *Git) DeleteReleaseAndTag(tag string) error {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+
+	release, err := g.getReleaseByTag(tag)
+	if err != nil {
+		return err
+	}
+	if release != nil {
+		logging.Info("Deleting release %s", release.GetName())
+		if _, err := g.client.Repositories.DeleteRelease(context.Background(), owner, GetRepo(), release.GetID()); err != nil {
+			return fmt.Errorf("failed to delete release for tag %s: %w", tag, err)
+		}
+	}
+
+	logging.Info("Deleting tag %s", tag)
+	res, err := g.client.Git.DeleteRef(context.Background(), owner, GetRepo(), "tags/"+tag)
+	if err != nil && !isNotFound(res) && !(res != nil && res.StatusCode == http.StatusUnprocessableEntity) {
+		return fmt.Errorf("failed to delete tag %s: %w", tag, err)
+	}
+
+	return nil
+}
+
+// MarkReleaseYanked keeps the GitHub release and tag for the given tag, but
+// marks the release as yanked so it is no longer presented as the latest.
+func (g *Git) MarkReleaseYanked(tag, reason string) error {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+
+	release, err := g.getReleaseByTag(tag)
+	if err != nil {
+		return err
+	}
+	if release == nil {
+		return fmt.Errorf("failed to get release for tag %s: release not found", tag)
+	}
+
+	if !strings.HasPrefix(release.GetName(), yankedReleasePrefix) {
+		release.Name = github.String(yankedReleasePrefix + release.GetName())
+		release.Body = github.String(fmt.Sprintf("> [!CAUTION]\n> %s\n\n%s", reason, release.GetBody()))
+	}
+	release.Prerelease = github.Bool(true)
+	release.MakeLatest = github.String("false")
+
+	if _, _, err := g.client.Repositories.EditRelease(context.Background(), owner, GetRepo(), release.GetID(), release); err != nil {
+		return fmt.Errorf("failed to mark release for tag %s as yanked: %w", tag, err)
+	}
+
+	return nil
+}
+
+// RevertCommit checks out branchName from the current HEAD, if provided, and
+// reverts the given commit into the worktree and index without committing, so
+// further changes can be included in the revert commit. Merge commits are
+// reverted against their first parent.
+func (g *Git) RevertCommit(commitHash, branchName string) error {
+	if g.repo == nil {
+		return fmt.Errorf("repo not cloned")
+	}
+
+	commit, err := g.repo.CommitObject(plumbing.NewHash(commitHash))
+	if err != nil {
+		return fmt.Errorf("commit %s was not found on %s: %w", commitHash, environment.GetRef(), err)
+	}
+
+	if branchName != "" {
+		if _, err := runGitCommand(g.context(), "checkout", "-B", branchName); err != nil {
+			return fmt.Errorf("error checking out branch %s: %w", branchName, err)
+		}
+	}
+
+	args := []string{"revert", "--no-commit"}
+	if commit.NumParents() > 1 {
+		args = append(args, "-m", "1")
+	}
+	args = append(args, commitHash)
+
+	logging.Info("Reverting commit %s", commitHash)
+
+	if _, err := runGitCommand(g.context(), args...); err != nil {
+		_, _ = runGitCommand(g.context(), "revert", "--abort")
+		return fmt.Errorf("error reverting commit %s: %w", commitHash, err)
+	}
+
+	return nil
+}
+
+// CommitAndPushRevert commits all pending changes and pushes them. In direct
+// mode the commit is pushed to the branch the action was run on, otherwise
+// branchName is pushed so it can be opened as a PR. Returns the commit hash.
+func (g *Git) CommitAndPushRevert(message, branchName string, direct bool) (string, error) {
+	if g.repo == nil {
+		return "", fmt.Errorf("repo not cloned")
+	}
+
+	if _, err := runGitCommand(g.context(), "add", "-A"); err != nil {
+		return "", fmt.Errorf("error adding changes: %w", err)
+	}
+
+	if _, err := runGitCommand(g.context(), "-c", "user.name="+speakeasyBotName, "-c", "user.email="+speakeasyBotEmail, "commit", "-m", message); err != nil {
+		return "", fmt.Errorf("error committing revert: %w", err)
+	}
+
+	headRef, err := g.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("error getting head ref: %w", err)
+	}
+
+	refspec := "HEAD:" + environment.GetRef()
+	args := []string{"push", "origin"}
+	if !direct {
+		refspec = fmt.Sprintf("HEAD:refs/heads/%s", branchName)
+		args = append(args, "--force")
+	}
+
+	if _, err := runGitCommand(g.context(), append(args, refspec)...); err != nil {
+		return "", g.pushErr(err)
+	}
+
+	return headRef.Hash().String(), nil
+}
+
+// CreateRollbackPR opens a PR merging the rollback branch into the branch the
+// action was run on, reusing an existing PR for the branch if there is one.
+func (g *Git) CreateRollbackPR(branchName, description, body string) (*github.PullRequest, error) {
+	owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
+	base := strings.TrimPrefix(environment.GetRef(), "refs/heads/")
+
+	prs, _, err := g.client.PullRequests.List(context.Background(), owner, GetRepo(), &github.PullRequestListOptions{
+		Head:  fmt.Sprintf("%s:%s", owner, branchName),
+		Base:  base,
+		State: "open",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+	if len(prs) > 0 {
+		logging.Info("Found existing rollback PR %s", prs[0].GetHTMLURL())
+		return prs[0], nil
+	}
+
+	pr, _, err := g.client.PullRequests.Create(context.Background(), owner, GetRepo(), &github.NewPullRequest{
+		Title:               github.String(speakeasyRollbackPRTitle + description),
+		Body:                github.String(body),
+		Head:                github.String(branchName),
+		Base:                github.String(base),
+		MaintainerCanModify: github.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	return pr, nil
+}
+
+func isNotFound(res *github.Response) bool {
+	return res != nil && res.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,144 @@
+package git
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v63/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseTag(t *testing.T) {
+	tests := []struct {
+		lang    string
+		path    string
+		version string
+		want    string
+	}{
+		{lang: "typescript", path: ".", version: "1.2.3", want: "v1.2.3"},
+		{lang: "typescript", path: "", version: "v1.2.3", want: "v1.2.3"},
+		{lang: "python", path: "sdks/python", version: "1.2.3", want: "sdks/python/v1.2.3"},
+		{lang: "terraform", path: "terraform", version: "1.2.3", want: "v1.2.3"},
+		{lang: "cli", path: "cli", version: "1.2.3", want: "v1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, ReleaseTag(tt.lang, tt.path, tt.version))
+		})
+	}
+}
+
+func newTestGitHubClient(t *testing.T, handler http.HandlerFunc) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := github.NewClient(server.Client())
+	client.BaseURL = baseURL
+	client.UploadURL = baseURL
+
+	return client
+}
+
+func TestResolveTagCommit_PeelsAnnotatedTags(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/git/ref/tags/python/v1.2.3":
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/python/v1.2.3","object":{"type":"tag","sha":"tag-sha"}}`))
+		case "/repos/owner/repo/git/tags/tag-sha":
+			_, _ = w.Write([]byte(`{"sha":"tag-sha","object":{"type":"commit","sha":"commit-sha"}}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	g := &Git{client: client}
+	hash, err := g.ResolveTagCommit("python/v1.2.3")
+
+	require.NoError(t, err)
+	require.Equal(t, "commit-sha", hash)
+}
+
+func TestDeleteReleaseAndTag_ToleratesAlreadyDeleted(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	var requests []string
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/repos/owner/repo/releases" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+
+	g := &Git{client: client}
+	require.NoError(t, g.DeleteReleaseAndTag("v1.2.3"))
+	require.Equal(t, []string{
+		"GET /repos/owner/repo/releases/tags/v1.2.3",
+		"GET /repos/owner/repo/releases",
+		"DELETE /repos/owner/repo/git/refs/tags/v1.2.3",
+	}, requests)
+}
+
+func TestDeleteReleaseAndTag_DeletesReleaseAndTag(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY_OWNER", "owner")
+	t.Setenv("GITHUB_REPOSITORY", "owner/repo")
+
+	var requests []string
+	client := newTestGitHubClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":42,"name":"typescript - v1.2.3"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	g := &Git{client: client}
+	require.NoError(t, g.DeleteReleaseAndTag("v1.2.3"))
+	require.Equal(t, []string{
+		"GET /repos/owner/repo/releases/tags/v1.2.3",
+		"DELETE /repos/owner/repo/releases/42",
+		"DELETE /repos/owner/repo/git/refs/tags/v1.2.3",
+	}, requests)
+}
+
+func TestFindRollbackCommit(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	repoDir := filepath.Join(workspace, "repo")
+	require.NoError(t, os.MkdirAll(repoDir, 0o755))
+
+	runGitCLI(t, repoDir, "init", "-q")
+	runGitCLI(t, repoDir, "commit", "-q", "--allow-empty", "-m", "chore: 🐝 Rollback go v1.2.30")
+
+	g := &Git{}
+	_, found, err := g.FindRollbackCommit("chore: 🐝 Rollback go v1.2.3")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	runGitCLI(t, repoDir, "commit", "-q", "--allow-empty", "-m", "chore: 🐝 Rollback go v1.2.3\n\nReverts abc123")
+	runGitCLI(t, repoDir, "commit", "-q", "--allow-empty", "-m", "feat: later change")
+	want := strings.TrimSpace(runGitCLI(t, repoDir, "rev-parse", "HEAD~1"))
+
+	hash, found, err := g.FindRollbackCommit("chore: 🐝 Rollback go v1.2.3")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, want, hash)
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+)
+
+const (
+	TagSigningFormatGPG = "gpg"
+	TagSigningFormatSSH = "ssh"
+)
+
+// tagSigningFormat returns the format of the configured tag signing key,
+// detecting it from the key itself if it isn't configured explicitly. An empty
+// string means tags aren't signed.
+func tagSigningFormat(key string) (string, error) {
+	if strings.TrimSpace(key) == "" {
+		return "", nil
+	}
+
+	switch format := environment.GetTagSigningFormat(); format {
+	case TagSigningFormatGPG, TagSigningFormatSSH:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported tag signing format %q, valid options are %q or %q", format, TagSigningFormatGPG, TagSigningFormatSSH)
+	}
+
+	switch {
+	case strings.Contains(key, "BEGIN PGP PRIVATE KEY BLOCK"):
+		return TagSigningFormatGPG, nil
+	case strings.Contains(key, "BEGIN OPENSSH PRIVATE KEY"):
+		return TagSigningFormatSSH, nil
+	}
+
+	return "", fmt.Errorf("unable to detect the format of the tag signing key, please set tag_signing_format")
+}
+
+// TagSigningEnabled returns true if a key to sign tags with was supplied.
+func TagSigningEnabled() bool {
+	return strings.TrimSpace(environment.GetTagSigningKey()) != ""
+}
+
+// createSignedTag creates an annotated tag signed with the configured key and
+// verifies the signature, deleting the tag again if verification fails.
+func (g *Git) createSignedTag(tag, hash string, tagger *object.Signature) error {
+	key := environment.GetTagSigningKey()
+
+	format, err := tagSigningFormat(key)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case TagSigningFormatGPG:
+		err = g.createGPGSignedTag(tag, hash, tagger, key, environment.GetTagSigningPassphrase())
+	case TagSigningFormatSSH:
+		err = g.createSSHSignedTag(tag, hash, tagger, key)
+	}
+	if err != nil {
+		return err
+	}
+
+	logging.Info("Verified %s signature of tag %s", format, tag)
+
+	return nil
+}
+
+func (g *Git) createGPGSignedTag(tag, hash string, tagger *object.Signature, armoredKey, passphrase string) error {
+	entity, err := readGPGSigningKey(armoredKey, passphrase)
+	if err != nil {
+		return err
+	}
+
+	ref, err := g.repo.CreateTag(tag, plumbing.NewHash(hash), &git.CreateTagOptions{
+		Message: tag,
+		Tagger:  tagger,
+		SignKey: entity,
+	})
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := armoredPublicKey(entity)
+	if err != nil {
+		return g.discardUnverifiedTag(tag, err)
+	}
+
+	tagObj, err := g.repo.TagObject(ref.Hash())
+	if err != nil {
+		return g.discardUnverifiedTag(tag, err)
+	}
+
+	if _, err := tagObj.Verify(publicKey); err != nil {
+		return g.discardUnverifiedTag(tag, err)
+	}
+
+	return nil
+}
+
+func readGPGSigningKey(armoredKey, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tag signing key: %w", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, fmt.Errorf("tag signing key does not contain a private key")
+	}
+
+	entity := entities[0]
+	if entity.PrivateKey.Encrypted {
+		if passphrase == "" {
+			return nil, fmt.Errorf("tag signing key is encrypted but no tag_signing_passphrase was provided")
+		}
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to decrypt tag signing key: %w", err)
+		}
+	}
+
+	return entity, nil
+}
+
+func armoredPublicKey(entity *openpgp.Entity) (string, error) {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := entity.Serialize(w); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// createSSHSignedTag signs the tag using git's SSH signing support, as go-git
+// can only sign with GPG keys. The key must not be passphrase protected.
+func (g *Git) createSSHSignedTag(tag, hash string, tagger *object.Signature, key string) error {
+	dir, err := os.MkdirTemp("", "speakeasy-tag-signing")
+	if err != nil {
+		return fmt.Errorf("failed to create tag signing directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "signing_key")
+	if err := os.WriteFile(keyPath, []byte(strings.TrimSpace(key)+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write tag signing key: %w", err)
+	}
+
+	publicKey, err := process.Command(g.context(), process.PhaseGit, "ssh-keygen", "-y", "-P", "", "-f", keyPath).Output()
+	if err != nil {
+		return fmt.Errorf("failed to read tag signing key, SSH signing keys must not be passphrase protected: %w", err)
+	}
+
+	allowedSignersPath := filepath.Join(dir, "allowed_signers")
+	if err := os.WriteFile(allowedSignersPath, []byte(fmt.Sprintf("%s %s", tagger.Email, publicKey)), 0o600); err != nil {
+		return fmt.Errorf("failed to write allowed signers file: %w", err)
+	}
+
+	if _, err := runGitCommand(g.context(),
+		"-c", "gpg.format=ssh",
+		"-c", "user.signingkey="+keyPath,
+		"-c", "user.name="+tagger.Name,
+		"-c", "user.email="+tagger.Email,
+		"tag", "-s", "-m", tag, tag, hash,
+	); err != nil {
+		return fmt.Errorf("failed to create signed tag: %w", err)
+	}
+
+	if _, err := runGitCommand(g.context(), "-c", "gpg.ssh.allowedSignersFile="+allowedSignersPath, "tag", "-v", tag); err != nil {
+		return g.discardUnverifiedTag(tag, err)
+	}
+
+	return nil
+}
+
+func (g *Git) discardUnverifiedTag(tag string, verifyErr error) error {
+	if err := g.repo.DeleteTag(tag); err != nil {
+		logging.Info("Failed to delete unverified tag %s: %v", tag, err)
+	}
+
+	return fmt.Errorf("failed to verify signature of tag %s: %w", tag, verifyErr)
+}
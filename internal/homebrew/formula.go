@@ -0,0 +1,187 @@
+package homebrew
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Artifact is a release archive of the CLI for a single OS and architecture.
+type Artifact struct {
+	OS     string
+	Arch   string
+	URL    string
+	SHA256 string
+}
+
+// Formula describes a Homebrew formula that installs the CLI from its release
+// archives.
+type Formula struct {
+	Name        string
+	Description string
+	Homepage    string
+	Version     string
+	License     string
+	Binary      string
+	Artifacts   []Artifact
+}
+
+var (
+	archiveRegex = regexp.MustCompile(`(?i)[_-](darwin|macos|linux)[_-](amd64|x86_64|arm64|aarch64|all)(?:[_-]v[0-9]+)?\.(?:tar\.gz|tgz|zip)$`)
+
+	formulaTemplate = template.Must(template.New("formula").Funcs(template.FuncMap{
+		"className": ClassName,
+		"cpuCheck":  cpuCheck,
+		"quote":     rubyString,
+	}).Parse(`# typed: false
+# frozen_string_literal: true
+
+# This file was generated by Speakeasy. DO NOT EDIT.
+class {{ className .Name }} < Formula
+  desc {{ quote .Description }}
+  homepage {{ quote .Homepage }}
+  version "{{ .Version }}"
+{{- if .License }}
+  license "{{ .License }}"
+{{- end }}
+{{ range $os := .Platforms }}
+  on_{{ $os.Name }} do
+{{- range $os.Artifacts }}
+{{- if eq .Arch "all" }}
+    url "{{ .URL }}"
+    sha256 "{{ .SHA256 }}"
+{{- else }}
+    if {{ cpuCheck .Arch }}
+      url "{{ .URL }}"
+      sha256 "{{ .SHA256 }}"
+    end
+{{- end }}
+{{- end }}
+  end
+{{ end }}
+  def install
+    bin.install "{{ .Binary }}"
+  end
+
+  test do
+    system "#{bin}/{{ .Binary }}", "--version"
+  end
+end
+`))
+)
+
+type platform struct {
+	Name      string
+	Artifacts []Artifact
+}
+
+// Render returns the Ruby source of the formula.
+func (f Formula) Render() (string, error) {
+	if len(f.Artifacts) == 0 {
+		return "", fmt.Errorf("no release archives found for the %s formula", f.Name)
+	}
+
+	var buf bytes.Buffer
+	if err := formulaTemplate.Execute(&buf, struct {
+		Formula
+		Platforms []platform
+	}{
+		Formula:   f,
+		Platforms: f.platforms(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to render the %s formula: %w", f.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+func (f Formula) platforms() []platform {
+	var platforms []platform
+	for _, os := range []string{"macos", "linux"} {
+		var artifacts []Artifact
+		for _, artifact := range f.Artifacts {
+			if artifact.OS == os {
+				artifacts = append(artifacts, artifact)
+			}
+		}
+		if len(artifacts) == 0 {
+			continue
+		}
+
+		sort.Slice(artifacts, func(i, j int) bool {
+			return artifacts[i].Arch < artifacts[j].Arch
+		})
+		platforms = append(platforms, platform{Name: os, Artifacts: artifacts})
+	}
+
+	return platforms
+}
+
+// ParseArchiveName returns the OS and architecture of a release archive from
+// its file name, following the goreleaser naming convention, e.g.
+// cli_Darwin_x86_64.tar.gz. Archives for other platforms are ignored.
+func ParseArchiveName(name string) (os, arch string, ok bool) {
+	matches := archiveRegex.FindStringSubmatch(path.Base(name))
+	if matches == nil {
+		return "", "", false
+	}
+
+	os = "linux"
+	if o := strings.ToLower(matches[1]); o == "darwin" || o == "macos" {
+		os = "macos"
+	}
+
+	switch strings.ToLower(matches[2]) {
+	case "amd64", "x86_64":
+		arch = "amd64"
+	case "arm64", "aarch64":
+		arch = "arm64"
+	default:
+		arch = "all"
+	}
+
+	return os, arch, true
+}
+
+// ClassName returns the Ruby class name Homebrew expects for a formula name,
+// e.g. my-cli becomes MyCli and my-cli@2 becomes MyCliAT2.
+func ClassName(name string) string {
+	name = strings.ReplaceAll(name, "@", "AT")
+
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+func cpuCheck(arch string) string {
+	switch arch {
+	case "amd64":
+		return "Hardware::CPU.intel?"
+	default:
+		return "Hardware::CPU.arm?"
+	}
+}
+
+// rubyString returns s as a double quoted Ruby string literal, escaping
+// interpolation.
+func rubyString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `#`, `\#`)
+	return `"` + replacer.Replace(s) + `"`
+}
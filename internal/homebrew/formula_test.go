@@ -0,0 +1,115 @@
+package homebrew
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormula_Render(t *testing.T) {
+	formula, err := Formula{
+		Name:        "acme-cli",
+		Description: `The "acme" CLI #1`,
+		Homepage:    "https://github.com/acme/cli",
+		Version:     "1.2.3",
+		Binary:      "acme",
+		Artifacts: []Artifact{
+			{OS: "linux", Arch: "arm64", URL: "https://example.com/acme_Linux_arm64.tar.gz", SHA256: "c"},
+			{OS: "macos", Arch: "arm64", URL: "https://example.com/acme_Darwin_arm64.tar.gz", SHA256: "b"},
+			{OS: "macos", Arch: "amd64", URL: "https://example.com/acme_Darwin_x86_64.tar.gz", SHA256: "a"},
+		},
+	}.Render()
+	require.NoError(t, err)
+
+	assert.Equal(t, `# typed: false
+# frozen_string_literal: true
+
+# This file was generated by Speakeasy. DO NOT EDIT.
+class AcmeCli < Formula
+  desc "The \"acme\" CLI \#1"
+  homepage "https://github.com/acme/cli"
+  version "1.2.3"
+
+  on_macos do
+    if Hardware::CPU.intel?
+      url "https://example.com/acme_Darwin_x86_64.tar.gz"
+      sha256 "a"
+    end
+    if Hardware::CPU.arm?
+      url "https://example.com/acme_Darwin_arm64.tar.gz"
+      sha256 "b"
+    end
+  end
+
+  on_linux do
+    if Hardware::CPU.arm?
+      url "https://example.com/acme_Linux_arm64.tar.gz"
+      sha256 "c"
+    end
+  end
+
+  def install
+    bin.install "acme"
+  end
+
+  test do
+    system "#{bin}/acme", "--version"
+  end
+end
+`, formula)
+}
+
+func TestFormula_RenderUniversalBinary(t *testing.T) {
+	formula, err := Formula{
+		Name:    "acme",
+		Version: "1.0.0",
+		Binary:  "acme",
+		License: "MIT",
+		Artifacts: []Artifact{
+			{OS: "macos", Arch: "all", URL: "https://example.com/acme_Darwin_all.tar.gz", SHA256: "a"},
+		},
+	}.Render()
+	require.NoError(t, err)
+
+	assert.Contains(t, formula, "  license \"MIT\"\n")
+	assert.Contains(t, formula, "  on_macos do\n    url \"https://example.com/acme_Darwin_all.tar.gz\"\n    sha256 \"a\"\n  end\n")
+	assert.NotContains(t, formula, "on_linux")
+}
+
+func TestFormula_RenderNoArtifacts(t *testing.T) {
+	_, err := Formula{Name: "acme"}.Render()
+	assert.Error(t, err)
+}
+
+func TestParseArchiveName(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantOS   string
+		wantArch string
+		wantOK   bool
+	}{
+		{name: "acme_Darwin_x86_64.tar.gz", wantOS: "macos", wantArch: "amd64", wantOK: true},
+		{name: "acme_1.2.3_darwin_arm64.zip", wantOS: "macos", wantArch: "arm64", wantOK: true},
+		{name: "acme_Linux_amd64_v1.tar.gz", wantOS: "linux", wantArch: "amd64", wantOK: true},
+		{name: "acme-linux-aarch64.tgz", wantOS: "linux", wantArch: "arm64", wantOK: true},
+		{name: "acme_Darwin_all.tar.gz", wantOS: "macos", wantArch: "all", wantOK: true},
+		{name: "acme_Windows_x86_64.zip"},
+		{name: "acme_Linux_386.tar.gz"},
+		{name: "checksums.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os, arch, ok := ParseArchiveName(tt.name)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantOS, os)
+			assert.Equal(t, tt.wantArch, arch)
+		})
+	}
+}
+
+func TestClassName(t *testing.T) {
+	assert.Equal(t, "AcmeCli", ClassName("acme-cli"))
+	assert.Equal(t, "AcmeCliAT2", ClassName("acme_cli@2"))
+	assert.Equal(t, "Acme", ClassName("acme"))
+}
@@ -1,21 +1,178 @@
+// Package logging writes the action's log, as text or JSON lines, along with
+// the GitHub Actions workflow commands grouping, annotating and masking it.
+// Only Annotation adds annotations to the workflow run.
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
 )
 
-func Info(msg string, args ...interface{}) {
-	fmt.Println("INFO: ", fmt.Sprintf(msg, args...))
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+var (
+	mu  sync.Mutex
+	out io.Writer = os.Stdout
+)
+
+// Logger logs messages with key-value fields.
+type Logger struct {
+	fields []any
+}
+
+// With returns a logger adding the key-value pairs to every message.
+func With(keyvals ...any) *Logger {
+	return (&Logger{}).With(keyvals...)
+}
+
+func (l *Logger) With(keyvals ...any) *Logger {
+	return &Logger{fields: append(slices.Clone(l.fields), keyvals...)}
+}
+
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(LevelDebug, msg, args...)
+}
+
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(LevelInfo, msg, args...)
+}
+
+func (l *Logger) Warn(msg string, args ...any) {
+	l.log(LevelWarn, msg, args...)
+}
+
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(LevelError, msg, args...)
+}
+
+func Debug(msg string, args ...any) {
+	(&Logger{}).log(LevelDebug, msg, args...)
+}
+
+func Info(msg string, args ...any) {
+	(&Logger{}).log(LevelInfo, msg, args...)
+}
+
+func Warn(msg string, args ...any) {
+	(&Logger{}).log(LevelWarn, msg, args...)
 }
 
-func Error(msg string, args ...interface{}) {
-	fmt.Println("ERROR: ", fmt.Sprintf(msg, args...))
+func Error(msg string, args ...any) {
+	(&Logger{}).log(LevelError, msg, args...)
 }
 
-func Debug(msg string, args ...interface{}) {
-	if environment.IsDebugMode() {
-		fmt.Println("::debug::", fmt.Sprintf(msg, args...))
+func (l *Logger) log(level Level, msg string, args ...any) {
+	if level == LevelDebug && !environment.IsDebugMode() {
+		return
+	}
+
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	msg = redact.String(msg)
+
+	if environment.GetLogFormat() == "json" {
+		writeLine(l.json(level, msg))
+		return
+	}
+
+	if fields := l.text(); fields != "" {
+		msg += " " + fields
 	}
+
+	switch level {
+	case LevelDebug:
+		writeLine("::debug::" + escapeData(msg))
+	case LevelInfo:
+		writeLine("INFO:  " + msg)
+	case LevelWarn:
+		writeLine("WARN:  " + msg)
+	default:
+		writeLine("ERROR:  " + msg)
+	}
+}
+
+func (l *Logger) text() string {
+	var b strings.Builder
+	for i := 0; i < len(l.fields); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		key, value := fmt.Sprint(l.fields[i]), "<missing>"
+		if i+1 < len(l.fields) {
+			value = redact.String(fmt.Sprint(l.fields[i+1]))
+		}
+		if strings.ContainsAny(value, " \t\n\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+
+		b.WriteString(key + "=" + value)
+	}
+
+	return b.String()
+}
+
+func (l *Logger) json(level Level, msg string) string {
+	entry := map[string]any{}
+	for i := 0; i < len(l.fields); i += 2 {
+		var value any = "<missing>"
+		if i+1 < len(l.fields) {
+			value = l.fields[i+1]
+			if _, ok := value.(error); ok {
+				value = fmt.Sprint(value)
+			}
+			if s, ok := value.(string); ok {
+				value = redact.String(s)
+			}
+		}
+		entry[fmt.Sprint(l.fields[i])] = value
+	}
+
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"level":%q,"msg":%q}`, level.String(), msg)
+	}
+
+	return string(data)
+}
+
+func writeLine(line string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, _ = fmt.Fprintln(out, line)
 }
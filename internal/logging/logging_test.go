@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := out
+	out = &buf
+	t.Cleanup(func() { out = previous })
+
+	return &buf
+}
+
+func TestLoggerText(t *testing.T) {
+	t.Setenv("INPUT_LOG_FORMAT", "")
+	t.Setenv("INPUT_DEBUG", "")
+	t.Setenv("RUNNER_DEBUG", "")
+	t.Cleanup(redact.Load)
+	t.Setenv("INPUT_GITHUB_ACCESS_TOKEN", "ghp_token123")
+	redact.Load()
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "info",
+			log:  func() { Info("Generating %s SDK", "go") },
+			want: "INFO:  Generating go SDK\n",
+		},
+		{
+			name: "fields",
+			log:  func() { With("target", "go", "dir", "sdks/go").Info("Generating SDK") },
+			want: "INFO:  Generating SDK target=go dir=sdks/go\n",
+		},
+		{
+			name: "quoted field",
+			log:  func() { With("error", "not found").Error("failed") },
+			want: "ERROR:  failed error=\"not found\"\n",
+		},
+		{
+			name: "missing field value",
+			log:  func() { With("target").Info("done") },
+			want: "INFO:  done target=<missing>\n",
+		},
+		{
+			name: "warning",
+			log:  func() { Warn("line one\nline two") },
+			want: "WARN:  line one\nline two\n",
+		},
+		{
+			name: "debug is hidden",
+			log:  func() { Debug("details") },
+			want: "",
+		},
+		{
+			name: "secrets are redacted",
+			log:  func() { With("url", "https://ghp_token123@github.com").Info("token %s", "ghp_token123") },
+			want: "INFO:  token *** url=https://***@github.com\n",
+		},
+		{
+			name: "literal percent without args",
+			log:  func() { Info("100% done") },
+			want: "INFO:  100% done\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureOutput(t)
+			tt.log()
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestLoggerDebug(t *testing.T) {
+	t.Setenv("INPUT_LOG_FORMAT", "")
+	t.Setenv("INPUT_DEBUG", "true")
+
+	buf := captureOutput(t)
+	With("phase", "setup").Debug("details")
+
+	assert.Equal(t, "::debug::details phase=setup\n", buf.String())
+}
+
+func TestLoggerJSON(t *testing.T) {
+	t.Setenv("INPUT_LOG_FORMAT", "json")
+	t.Setenv("INPUT_DEBUG", "")
+	t.Setenv("RUNNER_DEBUG", "")
+
+	buf := captureOutput(t)
+	logger := With("target", "go")
+	logger.With("attempt", 2).Warn("retrying %s", "push")
+	logger.Info("done")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "warn", entry["level"])
+	assert.Equal(t, "retrying push", entry["msg"])
+	assert.Equal(t, "go", entry["target"])
+	assert.Equal(t, float64(2), entry["attempt"])
+	assert.NotEmpty(t, entry["time"])
+
+	// Fields added to a derived logger don't leak into its parent
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.NotContains(t, lines[1], "attempt")
+	assert.Equal(t, "info", entry["level"])
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+)
+
+var groupDepth int
+
+// Group starts a collapsible group of log lines, returning the function ending
+// it. GitHub doesn't nest groups, so groups started within a group are logged
+// as part of it.
+func Group(name string, args ...any) func() {
+	if len(args) > 0 {
+		name = fmt.Sprintf(name, args...)
+	}
+
+	mu.Lock()
+	groupDepth++
+	nested := groupDepth > 1
+	mu.Unlock()
+
+	if nested {
+		Info("%s", name)
+	} else {
+		writeLine("::group::" + escapeData(redact.String(name)))
+	}
+
+	ended := false
+	return func() {
+		if ended {
+			return
+		}
+		ended = true
+
+		mu.Lock()
+		groupDepth--
+		mu.Unlock()
+
+		if !nested {
+			writeLine("::endgroup::")
+		}
+	}
+}
+
+// Annotation locates a message in a file of the repository, shown on the
+// workflow run summary and pull request diffs.
+type Annotation struct {
+	Title     string
+	File      string
+	Line      int
+	EndLine   int
+	Column    int
+	EndColumn int
+}
+
+func (a Annotation) Notice(msg string, args ...any) {
+	a.write("notice", msg, args...)
+}
+
+func (a Annotation) Warning(msg string, args ...any) {
+	a.write("warning", msg, args...)
+}
+
+func (a Annotation) Error(msg string, args ...any) {
+	a.write("error", msg, args...)
+}
+
+func (a Annotation) write(command, msg string, args ...any) {
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+
+	var props []string
+	add := func(key, value string) {
+		if value != "" {
+			props = append(props, key+"="+escapeProperty(redact.String(value)))
+		}
+	}
+	addInt := func(key string, value int) {
+		if value > 0 {
+			props = append(props, fmt.Sprintf("%s=%d", key, value))
+		}
+	}
+
+	add("title", a.Title)
+	add("file", a.File)
+	addInt("line", a.Line)
+	addInt("endLine", a.EndLine)
+	addInt("col", a.Column)
+	addInt("endColumn", a.EndColumn)
+
+	line := "::" + command
+	if len(props) > 0 {
+		line += " " + strings.Join(props, ",")
+	}
+
+	writeLine(line + "::" + escapeData(redact.String(msg)))
+}
+
+// Mask hides the value from the rest of the job log.
+func Mask(value string) {
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			writeLine("::add-mask::" + escapeData(line))
+		}
+	}
+}
+
+// MaskSecrets hides the secrets known to the action from the job log, including
+// the output of steps run after it.
+func MaskSecrets() {
+	for _, secret := range redact.Secrets() {
+		Mask(secret)
+	}
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
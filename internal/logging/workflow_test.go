@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	t.Setenv("INPUT_LOG_FORMAT", "")
+	buf := captureOutput(t)
+
+	endGroup := Group("Release %s", "go")
+	endNested := Group("Upload assets")
+	Info("uploading")
+	endNested()
+	endGroup()
+	endGroup()
+
+	assert.Equal(t, "::group::Release go\nINFO:  Upload assets\nINFO:  uploading\n::endgroup::\n", buf.String())
+	assert.Zero(t, groupDepth)
+}
+
+func TestAnnotation(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func()
+		want  string
+	}{
+		{
+			name: "warning with location",
+			write: func() {
+				Annotation{Title: "duplicate-schema", File: "specs/openapi.yaml", Line: 12, Column: 5}.Warning("schema %s is duplicated", "Pet")
+			},
+			want: "::warning title=duplicate-schema,file=specs/openapi.yaml,line=12,col=5::schema Pet is duplicated\n",
+		},
+		{
+			name: "error without location",
+			write: func() {
+				Annotation{Title: "failed"}.Error("%v", "run failed\nexit status 1")
+			},
+			want: "::error title=failed::run failed%0Aexit status 1\n",
+		},
+		{
+			name: "escaped properties",
+			write: func() {
+				Annotation{Title: "a: b, 100%"}.Notice("hint")
+			},
+			want: "::notice title=a%3A b%2C 100%25::hint\n",
+		},
+		{
+			name: "no properties",
+			write: func() {
+				Annotation{}.Notice("hint")
+			},
+			want: "::notice::hint\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := captureOutput(t)
+			tt.write()
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	buf := captureOutput(t)
+
+	Mask("first-line\nsecond-line\n")
+	Mask("")
+
+	assert.Equal(t, "::add-mask::first-line\n::add-mask::second-line\n", buf.String())
+}
+
+func TestMaskSecrets(t *testing.T) {
+	t.Cleanup(redact.Load)
+	t.Setenv("INPUT_GITHUB_ACCESS_TOKEN", "ghp_token123")
+	t.Setenv("INPUT_MIRROR_ACCESS_TOKEN", "")
+	t.Setenv("INPUT_OPENAPI_DOC_AUTH_TOKEN", "")
+	t.Setenv("INPUT_TAG_SIGNING_PASSPHRASE", "")
+	t.Setenv("PR_CREATION_PAT", "")
+	t.Setenv("SPEAKEASY_API_KEY", "")
+	t.Setenv("INPUT_CLI_ENVIRONMENT_VARIABLES", "")
+	redact.Load()
+
+	buf := captureOutput(t)
+	MaskSecrets()
+
+	assert.Contains(t, buf.String(), "::add-mask::ghp_token123\n")
+}
@@ -0,0 +1,198 @@
+// Package process runs subprocesses in their own process groups, bounded by
+// per-phase timeouts and cancelled along with the action when it receives
+// SIGTERM or SIGINT.
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+)
+
+// Phase groups subprocesses sharing a timeout, configured by the timeouts input.
+type Phase string
+
+const (
+	// PhaseSetup installs the tooling generation depends on.
+	PhaseSetup Phase = "setup"
+	// PhaseGenerate runs the speakeasy cli and the generators it drives.
+	PhaseGenerate Phase = "generate"
+	// PhaseProbe runs the speakeasy cli to inspect what it supports.
+	PhaseProbe Phase = "probe"
+	// PhaseGit runs git commands against the repository.
+	PhaseGit Phase = "git"
+	// PhaseRelease builds and uploads release artifacts.
+	PhaseRelease Phase = "release"
+)
+
+// waitDelay is how long a signalled process group has to exit before it is
+// killed.
+const waitDelay = 10 * time.Second
+
+var (
+	rootMu sync.RWMutex
+	root   = context.Background()
+)
+
+// signalError is the cancellation cause of a context cancelled by a signal.
+type signalError struct {
+	signal os.Signal
+}
+
+func (e *signalError) Error() string {
+	return fmt.Sprintf("received %s", e.signal)
+}
+
+// NotifyContext returns a context cancelled when the action receives SIGTERM or
+// SIGINT, which is then forwarded to running subprocesses. It also becomes the
+// context returned by Context.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			logging.Info("Received %s, stopping running commands", sig)
+			cancel(&signalError{signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	rootMu.Lock()
+	root = ctx
+	rootMu.Unlock()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel(context.Canceled)
+	}
+}
+
+// Context returns the context of the action, for code without one of its own.
+func Context() context.Context {
+	rootMu.RLock()
+	defer rootMu.RUnlock()
+
+	return root
+}
+
+// Cmd is an exec.Cmd bounded by the timeout of its phase.
+type Cmd struct {
+	*exec.Cmd
+
+	phase   Phase
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// Command returns a command run in its own process group. The group is sent
+// the signal the action received when ctx is cancelled, or SIGTERM when the
+// timeout of the phase elapses, and killed if it hasn't exited waitDelay later.
+func Command(ctx context.Context, phase Phase, name string, arg ...string) *Cmd {
+	timeout := environment.GetPhaseTimeout(string(phase))
+
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	cmd := exec.CommandContext(ctx, name, arg...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return signalProcessGroup(cmd, cancelSignal(ctx))
+	}
+	cmd.WaitDelay = waitDelay
+
+	return &Cmd{Cmd: cmd, phase: phase, timeout: timeout, ctx: ctx, cancel: cancel}
+}
+
+func (c *Cmd) Run() error {
+	defer c.cancel()
+	defer c.redactStreams()()
+	return c.wrap(c.Cmd.Run())
+}
+
+func (c *Cmd) Output() ([]byte, error) {
+	defer c.cancel()
+	defer c.redactStreams()()
+	out, err := c.Cmd.Output()
+	return out, c.wrap(err)
+}
+
+// CombinedOutput returns the output of the command with secrets redacted, as
+// it usually ends up in errors or the job log.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	defer c.cancel()
+	out, err := c.Cmd.CombinedOutput()
+	return []byte(redact.String(string(out))), c.wrap(err)
+}
+
+// redactStreams redacts secrets from output streamed to the job log, returning
+// a function flushing the redacted streams.
+func (c *Cmd) redactStreams() func() {
+	var writers []*redact.Writer
+	if c.Stdout == os.Stdout {
+		w := redact.NewWriter(os.Stdout)
+		c.Stdout = w
+		writers = append(writers, w)
+	}
+	if c.Stderr == os.Stderr {
+		w := redact.NewWriter(os.Stderr)
+		c.Stderr = w
+		writers = append(writers, w)
+	}
+
+	return func() {
+		for _, w := range writers {
+			_ = w.Close()
+		}
+	}
+}
+
+// wrap explains why a command was stopped, redacting secrets from the error.
+func (c *Cmd) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	err = redact.Error(err)
+
+	if errors.Is(c.ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s timed out after %s: %w", c.phase, c.timeout, err)
+	}
+
+	var sigErr *signalError
+	if errors.As(context.Cause(c.ctx), &sigErr) {
+		return fmt.Errorf("cancelled after the action %s: %w", sigErr, err)
+	}
+
+	if errors.Is(c.ctx.Err(), context.Canceled) {
+		return fmt.Errorf("cancelled: %w", err)
+	}
+
+	return err
+}
+
+func cancelSignal(ctx context.Context) os.Signal {
+	var sigErr *signalError
+	if errors.As(context.Cause(ctx), &sigErr) {
+		return sigErr.signal
+	}
+
+	return syscall.SIGTERM
+}
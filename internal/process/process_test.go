@@ -0,0 +1,75 @@
+//go:build unix
+
+package process
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_PhaseTimeout(t *testing.T) {
+	t.Setenv("INPUT_TIMEOUTS", "generate=100ms")
+
+	start := time.Now()
+	err := Command(context.Background(), PhaseGenerate, "sleep", "10").Run()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "generate timed out after 100ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestCommand_NoTimeout(t *testing.T) {
+	t.Setenv("INPUT_TIMEOUTS", "generate=100ms")
+
+	out, err := Command(context.Background(), PhaseGit, "sh", "-c", "sleep 0.2 && echo done").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "done\n", string(out))
+}
+
+func TestCommand_CombinedOutputRedacted(t *testing.T) {
+	t.Cleanup(redact.Load)
+	t.Setenv("INPUT_GITHUB_ACCESS_TOKEN", "ghp_token123")
+	redact.Load()
+
+	out, err := Command(context.Background(), PhaseGit, "sh", "-c", "echo https://ghp_token123@github.com >&2; exit 1").CombinedOutput()
+	require.Error(t, err)
+	assert.Equal(t, "https://***@github.com\n", string(out))
+}
+
+func TestCommand_SignalsProcessGroup(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "signalled")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	// The child of the shell must be signalled too, which it records
+	cmd := Command(ctx, PhaseGenerate, "sh", "-c", `sh -c 'trap "touch `+marker+`; exit 0" TERM; while true; do sleep 0.05; done' & wait`)
+	require.NoError(t, cmd.Start())
+
+	time.Sleep(200 * time.Millisecond)
+	cancel(&signalError{signal: syscall.SIGTERM})
+
+	err := cmd.Wait()
+	require.Error(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(marker)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestCommand_CancelledBySignal(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(&signalError{signal: syscall.SIGTERM})
+
+	err := Command(ctx, PhaseGit, "sleep", "10").Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cancelled after the action received terminated")
+}
@@ -0,0 +1,150 @@
+package publishtargets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+)
+
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// lookup queries a registry for the published versions of a package.
+type lookup struct {
+	// baseURL is the registry API queried unless overridden with the
+	// registry_overrides or registry_base_urls inputs.
+	baseURL string
+	exists  func(baseURL string, pkg Package) (bool, error)
+}
+
+// CanCheckExists reports whether the registry can be queried for published
+// versions.
+func (r Registry) CanCheckExists() bool {
+	return r.lookup != nil
+}
+
+// BaseURL returns the URL the registry is queried at.
+func (r Registry) BaseURL() string {
+	if r.lookup == nil {
+		return ""
+	}
+
+	if r.baseURL != "" {
+		return r.baseURL
+	}
+
+	if baseURL := environment.GetRegistryBaseURL(r.Name); baseURL != "" {
+		return baseURL
+	}
+
+	return r.lookup.baseURL
+}
+
+// PackageExists reports whether the version of the package has already been
+// published to the registry.
+func (r Registry) PackageExists(pkg Package) (bool, error) {
+	if r.lookup == nil {
+		return false, fmt.Errorf("checking published versions on %s is not supported", r.Name)
+	}
+	if pkg.Name == "" || pkg.Version == "" {
+		return false, fmt.Errorf("package name and version are required to check %s", r.Name)
+	}
+
+	pkg.Version = strings.TrimPrefix(pkg.Version, "v")
+
+	return r.lookup.exists(r.BaseURL(), pkg)
+}
+
+// versionURL returns a lookup func that checks for the version of the package
+// at the URL built by format, which only exists once the version is published.
+func versionURL(format func(baseURL string, pkg Package) string) func(baseURL string, pkg Package) (bool, error) {
+	return func(baseURL string, pkg Package) (bool, error) {
+		resp, err := get(format(baseURL, pkg))
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode == http.StatusOK, nil
+	}
+}
+
+func npmVersionURL(baseURL string, pkg Package) string {
+	return fmt.Sprintf("%s/%s/%s", baseURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version))
+}
+
+func pypiVersionURL(baseURL string, pkg Package) string {
+	return fmt.Sprintf("%s/pypi/%s/%s/json", baseURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version))
+}
+
+func mavenVersionURL(baseURL string, pkg Package) string {
+	lastDotIndex := strings.LastIndex(pkg.Name, ".")
+	groupID := pkg.Name[:max(lastDotIndex, 0)]
+	artifactID := pkg.Name[lastDotIndex+1:]
+
+	return fmt.Sprintf("%s/%s/%s/%s/%s-%s.pom", baseURL, strings.ReplaceAll(groupID, ".", "/"), artifactID, pkg.Version, artifactID, pkg.Version)
+}
+
+func nugetVersionURL(baseURL string, pkg Package) string {
+	id := strings.ToLower(pkg.Name)
+	version := strings.ToLower(pkg.Version)
+
+	return fmt.Sprintf("%s/v3-flatcontainer/%s/%s/%s.nuspec", baseURL, url.PathEscape(id), url.PathEscape(version), url.PathEscape(id))
+}
+
+func rubygemsVersionURL(baseURL string, pkg Package) string {
+	return fmt.Sprintf("%s/api/v2/rubygems/%s/versions/%s.json", baseURL, url.PathEscape(pkg.Name), url.PathEscape(pkg.Version))
+}
+
+// packagistExists looks the version up in the package's metadata, as Packagist
+// has no endpoint per version.
+func packagistExists(baseURL string, pkg Package) (bool, error) {
+	resp, err := get(fmt.Sprintf("%s/p2/%s.json", baseURL, pkg.Name))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var metadata struct {
+		Packages map[string][]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return false, fmt.Errorf("failed to decode packagist metadata for %s: %w", pkg.Name, err)
+	}
+
+	for _, v := range metadata.Packages[pkg.Name] {
+		if strings.TrimPrefix(v.Version, "v") == pkg.Version {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// get requests the URL, failing on responses other than found and not found so
+// that an unavailable registry isn't mistaken for an unpublished version.
+func get(url string) (*http.Response, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to query %s: %s", url, resp.Status)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,107 @@
+package publishtargets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageExists(t *testing.T) {
+	tests := []struct {
+		target       string
+		registryName string
+		pkg          Package
+		path         string
+	}{
+		{target: "typescript", registryName: "npm", pkg: Package{Name: "@org/sdk", Version: "1.2.3"}, path: "/@org%2Fsdk/1.2.3"},
+		{target: "mcp-typescript", registryName: "npm", pkg: Package{Name: "org-mcp", Version: "1.2.3"}, path: "/org-mcp/1.2.3"},
+		{target: "python", registryName: "pypi", pkg: Package{Name: "org-sdk", Version: "1.2.3"}, path: "/pypi/org-sdk/1.2.3/json"},
+		{target: "java", registryName: "sonatype", pkg: Package{Name: "com.org.sdk", Version: "1.2.3"}, path: "/com/org/sdk/1.2.3/sdk-1.2.3.pom"},
+		{target: "csharp", registryName: "nuget", pkg: Package{Name: "Org.SDK", Version: "1.2.3-Beta"}, path: "/v3-flatcontainer/org.sdk/1.2.3-beta/org.sdk.nuspec"},
+		{target: "ruby", registryName: "gems", pkg: Package{Name: "org_sdk", Version: "1.2.3"}, path: "/api/v2/rubygems/org_sdk/versions/1.2.3.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() == tt.path {
+					_, _ = w.Write([]byte(`{}`))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+			t.Setenv("INPUT_REGISTRY_BASE_URLS", tt.registryName+"="+server.URL+"/")
+
+			r := ForTarget(tt.target)
+			require.True(t, r.CanCheckExists())
+			assert.Equal(t, server.URL, r.BaseURL())
+
+			exists, err := r.PackageExists(tt.pkg)
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			tt.pkg.Version = "9.9.9"
+			exists, err = r.PackageExists(tt.pkg)
+			require.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestPackageExists_Packagist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/p2/org/sdk.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"packages":{"org/sdk":[{"version":"v1.2.3"},{"version":"1.2.2"}]}}`))
+	}))
+	defer server.Close()
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "packagist="+server.URL)
+
+	r := ForTarget("php")
+
+	exists, err := r.PackageExists(Package{Name: "org/sdk", Version: "1.2.3"})
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = r.PackageExists(Package{Name: "org/sdk", Version: "1.2.4"})
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = r.PackageExists(Package{Name: "org/other", Version: "1.2.3"})
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestPackageExists_RegistryUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "pypi="+server.URL)
+
+	_, err := ForTarget("python").PackageExists(Package{Name: "org-sdk", Version: "1.2.3"})
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestPackageExists_Unsupported(t *testing.T) {
+	for _, target := range []string{"go", "swift", "terraform", "cli", "postman"} {
+		r := ForTarget(target)
+		assert.False(t, r.CanCheckExists(), target)
+
+		_, err := r.PackageExists(Package{Name: "pkg", Version: "1.0.0"})
+		assert.Error(t, err, target)
+	}
+}
+
+func TestBaseURL_Default(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "")
+
+	assert.Equal(t, "https://registry.npmjs.org", ForTarget("typescript").BaseURL())
+	assert.Equal(t, "https://repo1.maven.org/maven2", ForTarget("java").BaseURL())
+	assert.Equal(t, "", ForTarget("go").BaseURL())
+}
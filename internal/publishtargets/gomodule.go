@@ -0,0 +1,37 @@
+package publishtargets
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var majorVersionDirRegex = regexp.MustCompile(`^v([0-9]+)$`)
+
+// goModuleTag returns the tag a release of the Go module in moduleDir must use
+// for `go get` to resolve it. It is prefixed with the module directory, less
+// the major version subdirectory of the version if the module lives in one,
+// e.g. sdk/v2/go.mod is tagged sdk/v2.0.0.
+func goModuleTag(moduleDir, version string) string {
+	version = strings.TrimPrefix(version, "v")
+
+	prefix := filepath.ToSlash(filepath.Clean(moduleDir))
+	if prefix == "." {
+		prefix = ""
+	}
+
+	major, _, _ := strings.Cut(version, ".")
+	if matches := majorVersionDirRegex.FindStringSubmatch(path.Base(prefix)); matches != nil && matches[1] == major {
+		if n, err := strconv.Atoi(major); err == nil && n >= 2 {
+			prefix = strings.TrimSuffix(path.Dir(prefix), ".")
+		}
+	}
+
+	if prefix == "" {
+		return "v" + version
+	}
+
+	return prefix + "/v" + version
+}
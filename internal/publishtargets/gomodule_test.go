@@ -0,0 +1,37 @@
+package publishtargets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoModuleTag(t *testing.T) {
+	tests := []struct {
+		moduleDir string
+		version   string
+		want      string
+	}{
+		{moduleDir: ".", version: "1.2.3", want: "v1.2.3"},
+		{moduleDir: "go", version: "1.2.3", want: "go/v1.2.3"},
+		{moduleDir: "sdk/v2", version: "2.0.0", want: "sdk/v2.0.0"},
+		{moduleDir: "v2", version: "2.1.0", want: "v2.1.0"},
+		{moduleDir: "sdk/v2", version: "3.0.0", want: "sdk/v2/v3.0.0"},
+		{moduleDir: "sdk/v1", version: "1.0.0", want: "sdk/v1/v1.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, goModuleTag(tt.moduleDir, tt.version))
+		})
+	}
+}
+
+func TestGoRegistry_ModuleDir(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "org/repo")
+	t.Setenv("INPUT_MIRROR_REPOSITORIES", "")
+
+	r := ForTarget("go")
+	assert.Equal(t, "sdk/v2.0.0", r.ReleaseTag("sdk/v2", "2.0.0"))
+	assert.Equal(t, "https://github.com/org/repo/releases/tag/sdk/v2.0.0", r.PackageURL(Package{Name: "github.com/org/repo/sdk/v2", Version: "2.0.0", Path: "sdk/v2"}))
+}
@@ -0,0 +1,83 @@
+package publishtargets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// preview publishes prerelease versions of a target's package for reviewing
+// generation PRs.
+type preview struct {
+	version func(version string, prNumber int, sha string) string
+	install func(pkg Package) string
+}
+
+// SupportsPreviews reports whether preview versions of the target's package can
+// be published to the registry. Targets released from git tags can be installed
+// from the PR branch instead.
+func (r Registry) SupportsPreviews() bool {
+	return r.preview != nil
+}
+
+// PreviewVersion returns the prerelease version a preview of the package is
+// published as for the head commit sha of the PR, e.g. 1.4.0-pr123.abc1234.
+func (r Registry) PreviewVersion(version string, prNumber int, sha string) string {
+	if r.preview == nil {
+		return ""
+	}
+
+	// Previews are prereleases of the release version, whatever its own prerelease
+	version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "+")
+	version, _, _ = strings.Cut(version, "-")
+	if len(sha) > 7 {
+		sha = sha[:7]
+	}
+
+	return r.preview.version(version, prNumber, sha)
+}
+
+// InstallCommand returns the command installing the version of the package.
+func (r Registry) InstallCommand(pkg Package) string {
+	if r.preview == nil {
+		return ""
+	}
+
+	return r.preview.install(pkg)
+}
+
+func semverPreview(version string, prNumber int, sha string) string {
+	return fmt.Sprintf("%s-pr%d.%s", version, prNumber, sha)
+}
+
+// pep440Preview uses the PR number as the release candidate and the commit as
+// the dev release, as PEP 440 versions can't contain arbitrary labels.
+func pep440Preview(version string, prNumber int, sha string) string {
+	dev, err := strconv.ParseUint(sha, 16, 64)
+	if err != nil {
+		dev = 0
+	}
+
+	return fmt.Sprintf("%src%d.dev%d", version, prNumber, dev)
+}
+
+// gemPreview separates the prerelease with a dot, as RubyGems treats versions
+// containing letters as prereleases and converts dashes to ".pre.".
+func gemPreview(version string, prNumber int, sha string) string {
+	return fmt.Sprintf("%s.pr%d.%s", version, prNumber, sha)
+}
+
+func installCommand(format string) func(pkg Package) string {
+	return func(pkg Package) string {
+		return fmt.Sprintf(format, pkg.Name, pkg.Version)
+	}
+}
+
+func mavenInstallCommand(pkg Package) string {
+	lastDotIndex := strings.LastIndex(pkg.Name, ".")
+	if lastDotIndex <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(`implementation("%s:%s:%s")`, pkg.Name[:lastDotIndex], pkg.Name[lastDotIndex+1:], pkg.Version)
+}
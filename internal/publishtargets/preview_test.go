@@ -0,0 +1,43 @@
+package publishtargets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewVersion(t *testing.T) {
+	tests := []struct {
+		target      string
+		version     string
+		wantVersion string
+		wantInstall string
+	}{
+		{target: "typescript", version: "1.4.0", wantVersion: "1.4.0-pr123.abc1234", wantInstall: "npm install @org/sdk@1.4.0-pr123.abc1234"},
+		{target: "mcp-typescript", version: "v1.4.0-beta.2", wantVersion: "1.4.0-pr123.abc1234", wantInstall: "npm install @org/sdk@1.4.0-pr123.abc1234"},
+		{target: "python", version: "1.4.0", wantVersion: "1.4.0rc123.dev180097588", wantInstall: "pip install @org/sdk==1.4.0rc123.dev180097588"},
+		{target: "csharp", version: "1.4.0+build.1", wantVersion: "1.4.0-pr123.abc1234", wantInstall: "dotnet add package @org/sdk --version 1.4.0-pr123.abc1234"},
+		{target: "ruby", version: "1.4.0", wantVersion: "1.4.0.pr123.abc1234", wantInstall: "gem install @org/sdk -v 1.4.0.pr123.abc1234"},
+		{target: "go", version: "1.4.0"},
+		{target: "swift", version: "1.4.0"},
+		{target: "terraform", version: "1.4.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			r := ForTarget(tt.target)
+			assert.Equal(t, tt.wantVersion != "", r.SupportsPreviews())
+
+			version := r.PreviewVersion(tt.version, 123, "abc1234def5678")
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.wantInstall, r.InstallCommand(Package{Name: "@org/sdk", Version: version}))
+		})
+	}
+}
+
+func TestPreviewVersion_Maven(t *testing.T) {
+	r := ForTarget("java")
+
+	version := r.PreviewVersion("1.4.0", 7, "0000001")
+	assert.Equal(t, "1.4.0-pr7.0000001", version)
+	assert.Equal(t, `implementation("com.org:sdk:1.4.0-pr7.0000001")`, r.InstallCommand(Package{Name: "com.org.sdk", Version: version}))
+}
@@ -0,0 +1,411 @@
+// Package publishtargets describes the package registries, such as npm or
+// PyPI, that generation targets are published to. Tags in the Speakeasy
+// registry are handled by package registry.
+package publishtargets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+)
+
+// Registry describes the package registry a target is published to, and how
+// its packages are named and linked to.
+type Registry struct {
+	// Target is the generation target, e.g. typescript.
+	Target string
+	// Name is the registry name reported by the publish-event action, e.g. npm,
+	// or the name of the private registry the target is published to instead.
+	Name string
+	// EventName is the registry name the publishing workflows report to the
+	// publish-event action. Unlike Name, it isn't changed by registry overrides.
+	EventName string
+	// Label names the registry in the releases section of RELEASES.md. Releases
+	// of targets without a label aren't listed there.
+	Label string
+
+	packageName    func(cfg map[string]any) string
+	packageURL     func(pkg Package) string
+	packageFromURL func(url, path string) string
+	releaseTag     func(path, version string) string
+	releaseRegex   *regexp.Regexp
+	lookup         *lookup
+	preview        *preview
+	// baseURL overrides the URL the registry is queried at.
+	baseURL string
+}
+
+// Package is a version of a target's package.
+type Package struct {
+	Name    string
+	Version string
+	// Path is the directory of the target relative to the root of the
+	// repository. For Go targets it is the directory of the module, see
+	// releases.PackagePath.
+	Path string
+}
+
+var registries = []Registry{
+	{
+		Target:      "cli",
+		Name:        "go",
+		packageName: configValue("packageName"),
+		packageURL: func(pkg Package) string {
+			return githubTagURL(".", pkg.Version, bareTag)
+		},
+		Label:          "CLI",
+		packageFromURL: githubPackage,
+		releaseTag:     bareTag,
+	},
+	{
+		Target:         "csharp",
+		Name:           "nuget",
+		packageName:    configValue("packageName"),
+		packageURL:     registryURL("https://www.nuget.org/packages/%s/%s"),
+		Label:          "NuGet",
+		packageFromURL: urlPackage(`^https://www\.nuget\.org/packages/(.*?)/`, ""),
+		lookup:         &lookup{baseURL: "https://api.nuget.org", exists: versionURL(nugetVersionURL)},
+		preview:        &preview{version: semverPreview, install: installCommand("dotnet add package %s --version %s")},
+	},
+	{
+		Target:      "go",
+		Name:        "go",
+		packageName: configValue("packageName"),
+		packageURL: func(pkg Package) string {
+			return githubTagURL(pkg.Path, pkg.Version, goModuleTag)
+		},
+		Label:          "Go",
+		packageFromURL: githubPackage,
+		releaseTag:     goModuleTag,
+	},
+	{
+		Target: "java",
+		Name:   "sonatype",
+		packageName: func(cfg map[string]any) string {
+			return fmt.Sprintf("%s.%s", cfg["groupID"], cfg["artifactID"])
+		},
+		packageURL: func(pkg Package) string {
+			lastDotIndex := strings.LastIndex(pkg.Name, ".")
+			if lastDotIndex <= 0 || lastDotIndex == len(pkg.Name)-1 || pkg.Version == "" {
+				return ""
+			}
+			groupID := pkg.Name[:lastDotIndex]      // everything before last occurrence of '.'
+			artifactID := pkg.Name[lastDotIndex+1:] // everything after last occurrence of '.'
+			return fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, pkg.Version)
+		},
+		Label:          "Maven Central",
+		packageFromURL: urlPackage(`^https://central\.sonatype\.com/artifact/(.*?)/(.*?)/`, "."),
+		lookup:         &lookup{baseURL: "https://repo1.maven.org/maven2", exists: versionURL(mavenVersionURL)},
+		preview:        &preview{version: semverPreview, install: mavenInstallCommand},
+	},
+	{
+		Target:      "mcp-typescript",
+		Name:        "npm",
+		packageName: configValue("packageName"),
+		packageURL:  registryURL("https://www.npmjs.com/package/%s/v/%s"),
+		lookup:      &lookup{baseURL: "https://registry.npmjs.org", exists: versionURL(npmVersionURL)},
+		preview:     &preview{version: semverPreview, install: installCommand("npm install %s@%s")},
+	},
+	{
+		Target:         "php",
+		Name:           "packagist",
+		packageName:    configValue("packageName"),
+		packageURL:     registryURL("https://packagist.org/packages/%s#v%s"),
+		Label:          "Composer",
+		packageFromURL: urlPackage(`^https://packagist\.org/packages/(.*?)#`, ""),
+		lookup:         &lookup{baseURL: "https://repo.packagist.org", exists: packagistExists},
+	},
+	{
+		Target:      "postman",
+		Name:        "postman",
+		packageName: configValue("packageName"),
+		packageURL:  func(Package) string { return "" },
+	},
+	{
+		Target:         "python",
+		Name:           "pypi",
+		packageName:    configValue("packageName"),
+		packageURL:     registryURL("https://pypi.org/project/%s/%s"),
+		Label:          "PyPI",
+		packageFromURL: urlPackage(`^https://pypi\.org/project/(.*?)/`, ""),
+		lookup:         &lookup{baseURL: "https://pypi.org", exists: versionURL(pypiVersionURL)},
+		preview:        &preview{version: pep440Preview, install: installCommand("pip install %s==%s")},
+	},
+	{
+		Target:         "ruby",
+		Name:           "gems",
+		packageName:    configValue("packageName"),
+		packageURL:     registryURL("https://rubygems.org/gems/%s/versions/%s"),
+		Label:          "Ruby Gems",
+		packageFromURL: urlPackage(`^https://rubygems\.org/gems/(.*?)/versions/`, ""),
+		lookup:         &lookup{baseURL: "https://rubygems.org", exists: versionURL(rubygemsVersionURL)},
+		preview:        &preview{version: gemPreview, install: installCommand("gem install %s -v %s")},
+	},
+	{
+		Target:      "swift",
+		Name:        "spm",
+		packageName: configValue("packageName"),
+		packageURL: func(pkg Package) string {
+			return githubTagURL(pkg.Path, pkg.Version, pathTag)
+		},
+		Label:          "Swift Package Manager",
+		packageFromURL: githubPackage,
+	},
+	{
+		Target: "terraform",
+		Name:   "terraform",
+		packageName: func(cfg map[string]any) string {
+			return fmt.Sprintf("%s/%s", cfg["author"], cfg["packageName"])
+		},
+		packageURL:     registryURL("https://registry.terraform.io/providers/%s/%s"),
+		Label:          "Terraform",
+		packageFromURL: urlPackage(`^https://registry\.terraform\.io/providers/(.*?)/(.*?)/`, "/"),
+		releaseTag:     bareTag,
+	},
+	{
+		Target:         "typescript",
+		Name:           "npm",
+		packageName:    configValue("packageName"),
+		packageURL:     registryURL("https://www.npmjs.com/package/%s/v/%s"),
+		Label:          "NPM",
+		packageFromURL: urlPackage(`^https://www\.npmjs\.com/package/(.*?)/v/`, ""),
+		lookup:         &lookup{baseURL: "https://registry.npmjs.org", exists: versionURL(npmVersionURL)},
+		preview:        &preview{version: semverPreview, install: installCommand("npm install %s@%s")},
+	},
+}
+
+func init() {
+	for i, r := range registries {
+		if r.Label != "" {
+			registries[i].releaseRegex = regexp.MustCompile(`- \[` + regexp.QuoteMeta(r.Label) + ` v(\d+\.\d+\.\d+(?:-?\w+(?:\.\w+)*)?)] (\S+) - (.*)`)
+		}
+	}
+}
+
+// ForTarget returns the registry of the given target, with any override from
+// the registry_overrides input applied. Targets without a known registry get
+// one named after the target, with no package URLs.
+func ForTarget(target string) Registry {
+	for _, r := range registries {
+		if r.Target == target {
+			r.EventName = r.Name
+			return r.withOverride()
+		}
+	}
+
+	r := Registry{
+		Target:      target,
+		Name:        target,
+		EventName:   target,
+		packageName: configValue("packageName"),
+		packageURL:  func(Package) string { return "" },
+	}
+
+	return r.withOverride()
+}
+
+// withOverride points the registry at the private registry the target is
+// published to instead, if it has one.
+func (r Registry) withOverride() Registry {
+	override, ok := environment.GetRegistryOverride(r.Target)
+	if !ok {
+		return r
+	}
+
+	if override.Name != "" {
+		r.Name = override.Name
+	}
+	if override.BaseURL != "" {
+		r.baseURL = override.BaseURL
+	}
+	if override.PackageURL != "" {
+		r.packageURL = templateURL(override.PackageURL)
+		r.packageFromURL = templatePackage(override.PackageURL)
+	}
+
+	return r
+}
+
+// ForPublishEvent returns the registry a publish-event for the given registry
+// name refers to, matching either the registry's original or overridden name.
+// Registries such as npm are shared by several targets, so the given targets,
+// generated in the published directory, must match exactly one registry. If no
+// registry name is given, the only given target is used.
+func ForPublishEvent(name string, targets []string) (Registry, bool) {
+	var matches []Registry
+	for _, target := range targets {
+		r := ForTarget(target)
+		if name == "" || r.Name == name || r.EventName == name {
+			matches = append(matches, r)
+		}
+	}
+
+	if len(matches) != 1 {
+		return Registry{}, false
+	}
+
+	return matches[0], true
+}
+
+// Targets returns the targets with a known registry, in alphabetical order.
+func Targets() []string {
+	targets := make([]string, 0, len(registries))
+	for _, r := range registries {
+		targets = append(targets, r.Target)
+	}
+
+	return targets
+}
+
+// PackageName returns the name of the target's package from its gen.yaml
+// language config.
+func (r Registry) PackageName(cfg map[string]any) string {
+	return r.packageName(cfg)
+}
+
+// PackageURL returns the URL of the version of the package on the registry, or
+// an empty string if it can't be linked to.
+func (r Registry) PackageURL(pkg Package) string {
+	return r.packageURL(pkg)
+}
+
+// ReleaseTag returns the git tag a release of the version of the target at path
+// is created with. Targets released with goreleaser are tagged at the root of
+// the repository, as it doesn't support prefixed tags.
+func (r Registry) ReleaseTag(path, version string) string {
+	version = strings.TrimPrefix(version, "v")
+	if r.releaseTag != nil {
+		return r.releaseTag(path, version)
+	}
+
+	return pathTag(path, version)
+}
+
+// ParseRelease finds the release of the target in a release section of
+// RELEASES.md, returning its package and URL.
+func (r Registry) ParseRelease(release string) (Package, string, bool) {
+	if r.releaseRegex == nil {
+		return Package{}, "", false
+	}
+
+	matches := r.releaseRegex.FindStringSubmatch(release)
+	if len(matches) != 4 {
+		return Package{}, "", false
+	}
+
+	url, path := matches[2], matches[3]
+	name := r.packageFromURL(url, path)
+	if name == "" {
+		return Package{}, "", false
+	}
+
+	return Package{Name: name, Version: matches[1], Path: path}, url, true
+}
+
+func configValue(key string) func(cfg map[string]any) string {
+	return func(cfg map[string]any) string {
+		return fmt.Sprintf("%s", cfg[key])
+	}
+}
+
+func registryURL(format string) func(pkg Package) string {
+	return func(pkg Package) string {
+		if pkg.Name == "" || pkg.Version == "" {
+			return ""
+		}
+
+		return fmt.Sprintf(format, pkg.Name, pkg.Version)
+	}
+}
+
+// githubTagURL returns the URL of the tag for a release of the target at path,
+// tagged by releaseTag, pointing at the target's mirror repository if it has
+// one.
+func githubTagURL(path, version string, releaseTag func(path, version string) string) string {
+	if version == "" {
+		return ""
+	}
+
+	repoPath := os.Getenv("GITHUB_REPOSITORY")
+	tag := fmt.Sprintf("v%s", version)
+	if mirror := environment.GetMirrorRepository(path); mirror != "" {
+		repoPath = mirror
+	} else if path != "" && filepath.Clean(path) != "." {
+		tag = releaseTag(filepath.Clean(path), version)
+	}
+
+	return fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
+}
+
+// urlPackage returns a func parsing the package name from a package URL. If the
+// pattern captures the name in several parts they are joined with sep.
+func urlPackage(pattern, sep string) func(url, path string) string {
+	re := regexp.MustCompile(pattern)
+
+	return func(url, _ string) string {
+		matches := re.FindStringSubmatch(url)
+		if len(matches) < 2 {
+			return ""
+		}
+
+		return strings.Join(matches[1:], sep)
+	}
+}
+
+var githubReleaseRegex = regexp.MustCompile(`^https://(github\.com/.*?)/releases/tag/`)
+
+// githubPackage returns the module path of a target released as a tag of its
+// GitHub repository, assuming the module lives at the target's path.
+func githubPackage(url, path string) string {
+	matches := githubReleaseRegex.FindStringSubmatch(url)
+	if len(matches) != 2 {
+		return ""
+	}
+
+	if path != "." {
+		return fmt.Sprintf("%s/%s", matches[1], strings.TrimPrefix(path, "./"))
+	}
+
+	return matches[1]
+}
+
+// templateURL returns a func filling in the {name}, {version} and {path}
+// placeholders of a package URL template.
+func templateURL(template string) func(pkg Package) string {
+	return func(pkg Package) string {
+		if pkg.Name == "" || pkg.Version == "" {
+			return ""
+		}
+
+		return strings.NewReplacer("{name}", pkg.Name, "{version}", pkg.Version, "{path}", pkg.Path).Replace(template)
+	}
+}
+
+// templatePackage returns a func parsing the package name from a URL built
+// from a package URL template.
+func templatePackage(template string) func(url, path string) string {
+	// Versions never contain a slash, unlike scoped package names
+	pattern := strings.NewReplacer(`\{name\}`, `(.*?)`, `\{version\}`, `[^/]+`, `\{path\}`, `.*?`).Replace(regexp.QuoteMeta(template))
+	if !strings.Contains(pattern, "(.*?)") {
+		return func(string, string) string { return "" }
+	}
+
+	return urlPackage("^"+pattern+"$", "")
+}
+
+func pathTag(path, version string) string {
+	tag := "v" + version
+	if path != "" && path != "." && path != "./" {
+		tag = fmt.Sprintf("%s/%s", path, tag)
+	}
+
+	return tag
+}
+
+func bareTag(_, version string) string {
+	return "v" + version
+}
@@ -0,0 +1,215 @@
+package publishtargets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForTarget(t *testing.T) {
+	tests := []struct {
+		target      string
+		wantName    string
+		cfg         map[string]any
+		wantPackage string
+	}{
+		{target: "cli", wantName: "go", cfg: map[string]any{"packageName": "github.com/org/cli"}, wantPackage: "github.com/org/cli"},
+		{target: "csharp", wantName: "nuget", cfg: map[string]any{"packageName": "Org.SDK"}, wantPackage: "Org.SDK"},
+		{target: "go", wantName: "go", cfg: map[string]any{"packageName": "github.com/org/sdk"}, wantPackage: "github.com/org/sdk"},
+		{target: "java", wantName: "sonatype", cfg: map[string]any{"groupID": "com.org", "artifactID": "sdk"}, wantPackage: "com.org.sdk"},
+		{target: "mcp-typescript", wantName: "npm", cfg: map[string]any{"packageName": "@org/mcp"}, wantPackage: "@org/mcp"},
+		{target: "php", wantName: "packagist", cfg: map[string]any{"packageName": "org/sdk"}, wantPackage: "org/sdk"},
+		{target: "postman", wantName: "postman", cfg: map[string]any{"packageName": "collection"}, wantPackage: "collection"},
+		{target: "python", wantName: "pypi", cfg: map[string]any{"packageName": "org-sdk"}, wantPackage: "org-sdk"},
+		{target: "ruby", wantName: "gems", cfg: map[string]any{"packageName": "org_sdk"}, wantPackage: "org_sdk"},
+		{target: "swift", wantName: "spm", cfg: map[string]any{"packageName": "OrgSDK"}, wantPackage: "OrgSDK"},
+		{target: "terraform", wantName: "terraform", cfg: map[string]any{"author": "org", "packageName": "provider"}, wantPackage: "org/provider"},
+		{target: "typescript", wantName: "npm", cfg: map[string]any{"packageName": "@org/sdk"}, wantPackage: "@org/sdk"},
+		{target: "unknown", wantName: "unknown", cfg: map[string]any{"packageName": "pkg"}, wantPackage: "pkg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			r := ForTarget(tt.target)
+			assert.Equal(t, tt.target, r.Target)
+			assert.Equal(t, tt.wantName, r.Name)
+			assert.Equal(t, tt.wantPackage, r.PackageName(tt.cfg))
+		})
+	}
+}
+
+func TestForPublishEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		registryName string
+		targets      []string
+		wantTarget   string
+		wantOK       bool
+	}{
+		{name: "npm typescript", registryName: "npm", targets: []string{"typescript"}, wantTarget: "typescript", wantOK: true},
+		{name: "npm mcp-typescript", registryName: "npm", targets: []string{"mcp-typescript"}, wantTarget: "mcp-typescript", wantOK: true},
+		{name: "go cli", registryName: "go", targets: []string{"cli"}, wantTarget: "cli", wantOK: true},
+		{name: "go sdk", registryName: "go", targets: []string{"go"}, wantTarget: "go", wantOK: true},
+		{name: "no registry name", registryName: "", targets: []string{"swift"}, wantTarget: "swift", wantOK: true},
+		{name: "registry not generated", registryName: "pypi", targets: []string{"typescript"}, wantOK: false},
+		{name: "registry shared by targets", registryName: "npm", targets: []string{"mcp-typescript", "typescript"}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := ForPublishEvent(tt.registryName, tt.targets)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantTarget, r.Target)
+		})
+	}
+}
+
+func TestPackageURL(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "org/repo")
+	t.Setenv("INPUT_MIRROR_REPOSITORIES", "sdks/swift=org/swift-sdk")
+
+	tests := []struct {
+		target  string
+		pkg     Package
+		wantURL string
+	}{
+		{target: "cli", pkg: Package{Name: "cli", Version: "1.0.0", Path: "cli"}, wantURL: "https://github.com/org/repo/releases/tag/v1.0.0"},
+		{target: "csharp", pkg: Package{Name: "Org.SDK", Version: "1.0.0"}, wantURL: "https://www.nuget.org/packages/Org.SDK/1.0.0"},
+		{target: "go", pkg: Package{Name: "github.com/org/repo/go", Version: "1.0.0", Path: "go"}, wantURL: "https://github.com/org/repo/releases/tag/go/v1.0.0"},
+		{target: "go", pkg: Package{Name: "github.com/org/repo", Version: "1.0.0", Path: "."}, wantURL: "https://github.com/org/repo/releases/tag/v1.0.0"},
+		{target: "java", pkg: Package{Name: "com.org.sdk", Version: "1.0.0"}, wantURL: "https://central.sonatype.com/artifact/com.org/sdk/1.0.0"},
+		{target: "java", pkg: Package{Name: "invalid", Version: "1.0.0"}, wantURL: ""},
+		{target: "mcp-typescript", pkg: Package{Name: "@org/mcp", Version: "1.0.0"}, wantURL: "https://www.npmjs.com/package/@org/mcp/v/1.0.0"},
+		{target: "php", pkg: Package{Name: "org/sdk", Version: "1.0.0"}, wantURL: "https://packagist.org/packages/org/sdk#v1.0.0"},
+		{target: "postman", pkg: Package{Name: "collection", Version: "1.0.0"}, wantURL: ""},
+		{target: "python", pkg: Package{Name: "org-sdk", Version: "1.0.0"}, wantURL: "https://pypi.org/project/org-sdk/1.0.0"},
+		{target: "ruby", pkg: Package{Name: "org_sdk", Version: "1.0.0"}, wantURL: "https://rubygems.org/gems/org_sdk/versions/1.0.0"},
+		{target: "swift", pkg: Package{Name: "OrgSDK", Version: "1.0.0", Path: "swift"}, wantURL: "https://github.com/org/repo/releases/tag/swift/v1.0.0"},
+		{target: "swift", pkg: Package{Name: "OrgSDK", Version: "1.0.0", Path: "sdks/swift"}, wantURL: "https://github.com/org/swift-sdk/releases/tag/v1.0.0"},
+		{target: "terraform", pkg: Package{Name: "org/provider", Version: "1.0.0"}, wantURL: "https://registry.terraform.io/providers/org/provider/1.0.0"},
+		{target: "typescript", pkg: Package{Name: "@org/sdk", Version: "1.0.0"}, wantURL: "https://www.npmjs.com/package/@org/sdk/v/1.0.0"},
+		{target: "typescript", pkg: Package{Name: "@org/sdk"}, wantURL: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.pkg.Name, func(t *testing.T) {
+			assert.Equal(t, tt.wantURL, ForTarget(tt.target).PackageURL(tt.pkg))
+		})
+	}
+}
+
+func TestReleaseTag(t *testing.T) {
+	tests := []struct {
+		target  string
+		path    string
+		version string
+		want    string
+	}{
+		{target: "typescript", path: ".", version: "1.2.3", want: "v1.2.3"},
+		{target: "typescript", path: "", version: "v1.2.3", want: "v1.2.3"},
+		{target: "python", path: "sdks/python", version: "1.2.3", want: "sdks/python/v1.2.3"},
+		{target: "go", path: "./go", version: "1.2.3", want: "go/v1.2.3"},
+		{target: "swift", path: "swift", version: "1.2.3", want: "swift/v1.2.3"},
+		{target: "terraform", path: "terraform", version: "1.2.3", want: "v1.2.3"},
+		{target: "cli", path: "cli", version: "1.2.3", want: "v1.2.3"},
+		{target: "unknown", path: "unknown", version: "1.2.3", want: "unknown/v1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, ForTarget(tt.target).ReleaseTag(tt.path, tt.version))
+		})
+	}
+}
+
+func TestParseRelease(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "org/repo")
+
+	tests := []struct {
+		target string
+		pkg    Package
+	}{
+		{target: "cli", pkg: Package{Name: "github.com/org/repo", Version: "1.0.0", Path: "."}},
+		{target: "csharp", pkg: Package{Name: "Org.SDK", Version: "1.0.0", Path: "csharp"}},
+		{target: "go", pkg: Package{Name: "github.com/org/repo/go", Version: "1.0.0-beta.1", Path: "go"}},
+		{target: "java", pkg: Package{Name: "com.org.sdk", Version: "1.0.0", Path: "java"}},
+		{target: "php", pkg: Package{Name: "org/sdk", Version: "1.0.0", Path: "php"}},
+		{target: "python", pkg: Package{Name: "org-sdk", Version: "1.0.0rc1", Path: "python"}},
+		{target: "ruby", pkg: Package{Name: "org_sdk", Version: "1.0.0", Path: "ruby"}},
+		{target: "swift", pkg: Package{Name: "github.com/org/repo/swift", Version: "1.0.0", Path: "swift"}},
+		{target: "terraform", pkg: Package{Name: "org/provider", Version: "1.0.0", Path: "."}},
+		{target: "typescript", pkg: Package{Name: "@org/sdk", Version: "1.0.0", Path: "typescript"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			r := ForTarget(tt.target)
+			url := r.PackageURL(tt.pkg)
+			release := fmt.Sprintf("### Releases\n- [%s v%s] %s - %s\n", r.Label, tt.pkg.Version, url, tt.pkg.Path)
+
+			pkg, gotURL, ok := r.ParseRelease(release)
+			require.True(t, ok)
+			assert.Equal(t, tt.pkg, pkg)
+			assert.Equal(t, url, gotURL)
+		})
+	}
+}
+
+func TestParseRelease_NotFound(t *testing.T) {
+	release := "### Releases\n- [PyPI v1.0.0] https://pypi.org/project/org-sdk/1.0.0 - .\n"
+
+	_, _, ok := ForTarget("typescript").ParseRelease(release)
+	assert.False(t, ok)
+
+	_, _, ok = ForTarget("mcp-typescript").ParseRelease(release)
+	assert.False(t, ok)
+
+	_, _, ok = ForTarget("python").ParseRelease("- [PyPI v1.0.0] https://example.com/org-sdk - .")
+	assert.False(t, ok)
+}
+
+func TestForTarget_Override(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_OVERRIDES", `{
+		"typescript": {
+			"name": "artifactory",
+			"base_url": "https://org.jfrog.io/artifactory/api/npm/npm/",
+			"package_url": "https://org.jfrog.io/ui/packages/npm:%2F%2F{name}/{version}"
+		},
+		"python": {"base_url": "https://pypi.example.com"}
+	}`)
+	t.Setenv("INPUT_REGISTRY_BASE_URLS", "npm=https://npm.example.com\npypi=https://ignored.example.com")
+
+	r := ForTarget("typescript")
+	assert.Equal(t, "artifactory", r.Name)
+	assert.Equal(t, "https://org.jfrog.io/artifactory/api/npm/npm", r.BaseURL())
+
+	pkg := Package{Name: "@org/sdk", Version: "1.0.0", Path: "typescript"}
+	url := r.PackageURL(pkg)
+	assert.Equal(t, "https://org.jfrog.io/ui/packages/npm:%2F%2F@org/sdk/1.0.0", url)
+
+	parsed, gotURL, ok := r.ParseRelease(fmt.Sprintf("- [NPM v1.0.0] %s - typescript", url))
+	require.True(t, ok)
+	assert.Equal(t, pkg, parsed)
+	assert.Equal(t, url, gotURL)
+
+	python := ForTarget("python")
+	assert.Equal(t, "pypi", python.Name)
+	assert.Equal(t, "https://pypi.example.com", python.BaseURL())
+	assert.Equal(t, "https://pypi.org/project/org-sdk/1.0.0", python.PackageURL(Package{Name: "org-sdk", Version: "1.0.0"}))
+
+	// Publish events match both the override's registry name and the original
+	// one the publishing workflows report
+	assert.Equal(t, "npm", r.EventName)
+	for _, name := range []string{"artifactory", "npm"} {
+		r, ok := ForPublishEvent(name, []string{"typescript"})
+		assert.True(t, ok, name)
+		assert.Equal(t, "typescript", r.Target)
+		assert.Equal(t, "artifactory", r.Name)
+	}
+
+	_, ok = ForPublishEvent("pypi", []string{"typescript"})
+	assert.False(t, ok)
+}
+
+func TestForTarget_InvalidOverride(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_OVERRIDES", `{"typescript":`)
+
+	assert.Equal(t, "npm", ForTarget("typescript").Name)
+}
@@ -0,0 +1,226 @@
+// Package redact removes secrets known to the action from subprocess output,
+// log messages and errors before they reach the job log.
+package redact
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+)
+
+// Mask replaces redacted secrets.
+const Mask = "***"
+
+// minSecretLength avoids redacting short values such as "true" or "1" set in
+// cli_environment_variables, which would mangle unrelated output.
+const minSecretLength = 6
+
+var (
+	mu      sync.RWMutex
+	loaded  bool
+	secrets []string
+	// replacer redacts the secrets, or is nil if there are none
+	replacer *strings.Replacer
+)
+
+// Load computes the secrets known to the action from its environment. It is
+// called once the environment is set up, and again by tests changing it;
+// otherwise the secrets are loaded on first use.
+func Load() {
+	values := []string{
+		environment.GetAccessToken(),
+		environment.GetMirrorAccessToken(),
+		environment.GetHomebrewTapAccessToken(),
+		environment.GetOpenAPIDocAuthToken(),
+		environment.GetTagSigningKey(),
+		environment.GetTagSigningPassphrase(),
+		environment.GetGithubOIDCRequestToken(),
+		os.Getenv("INPUT_OPENAI_API_KEY"),
+		os.Getenv("PR_CREATION_PAT"),
+		os.Getenv("SPEAKEASY_API_KEY"),
+	}
+
+	for _, env := range environment.SpeakeasyEnvVars() {
+		if _, value, ok := strings.Cut(env, "="); ok {
+			values = append(values, value)
+		}
+	}
+
+	seen := map[string]bool{}
+	var found []string
+	add := func(form string) {
+		if !seen[form] {
+			seen[form] = true
+			found = append(found, form)
+		}
+	}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength {
+			continue
+		}
+
+		for _, form := range []string{
+			value,
+			base64.StdEncoding.EncodeToString([]byte(value)),
+			base64.RawStdEncoding.EncodeToString([]byte(value)),
+			base64.URLEncoding.EncodeToString([]byte(value)),
+			base64.RawURLEncoding.EncodeToString([]byte(value)),
+			url.QueryEscape(value),
+			url.PathEscape(value),
+		} {
+			add(form)
+		}
+
+		// Keys such as the tag signing key span lines, which may be echoed
+		// on their own. Armor headers aren't secret.
+		for _, line := range strings.Split(value, "\n") {
+			if line = strings.TrimSpace(line); len(line) >= minSecretLength && !strings.HasPrefix(line, "-----") {
+				add(line)
+			}
+		}
+	}
+
+	slices.SortFunc(found, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	var r *strings.Replacer
+	if len(found) > 0 {
+		oldnew := make([]string, 0, len(found)*2)
+		for _, secret := range found {
+			oldnew = append(oldnew, secret, Mask)
+		}
+		r = strings.NewReplacer(oldnew...)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	loaded, secrets, replacer = true, found, r
+}
+
+func load() *strings.Replacer {
+	mu.RLock()
+	if loaded {
+		defer mu.RUnlock()
+		return replacer
+	}
+	mu.RUnlock()
+
+	Load()
+
+	return load()
+}
+
+// Secrets returns the secrets known to the action, in the forms they may be
+// echoed in, longest first so that encodings containing a shorter secret are
+// redacted whole.
+func Secrets() []string {
+	load()
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return slices.Clone(secrets)
+}
+
+// String redacts the known secrets from s.
+func String(s string) string {
+	r := load()
+	if r == nil {
+		return s
+	}
+
+	return r.Replace(s)
+}
+
+// Error returns err with the known secrets redacted from its message. The
+// original error is still available to errors.Is and errors.As.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := String(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// maxBuffered bounds the output buffered waiting for a newline, for output
+// such as progress bars that never ends a line.
+const maxBuffered = 64 * 1024
+
+// Writer redacts the known secrets from the output written to it. Output is
+// redacted a line at a time, so that secrets split across writes are still
+// found, and must be flushed with Close.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (r *Writer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf.Write(p)
+
+	if i := bytes.LastIndexAny(r.buf.Bytes(), "\r\n"); i >= 0 {
+		if err := r.flush(i + 1); err != nil {
+			return 0, err
+		}
+	} else if r.buf.Len() > maxBuffered {
+		if err := r.flush(r.buf.Len()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close writes any buffered output.
+func (r *Writer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.flush(r.buf.Len())
+}
+
+func (r *Writer) flush(n int) error {
+	if n == 0 {
+		return nil
+	}
+
+	chunk := String(string(r.buf.Next(n)))
+	_, err := r.w.Write([]byte(chunk))
+	return err
+}
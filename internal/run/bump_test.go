@@ -0,0 +1,37 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/speakeasy-api/versioning-reports/versioning"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpTypeForTarget(t *testing.T) {
+	// Targets released at the same version are told apart by their keys
+	report := &versioning.MergedVersionReport{Reports: []versioning.VersionReport{
+		{Key: "typescript", BumpType: versioning.BumpMinor, NewVersion: "1.3.0"},
+		{Key: "my-python", BumpType: versioning.BumpPatch, NewVersion: "1.3.0"},
+	}}
+	manual := versioning.BumpMajor
+
+	tests := []struct {
+		name       string
+		report     *versioning.MergedVersionReport
+		manualBump *versioning.BumpType
+		targetID   string
+		lang       string
+		want       string
+	}{
+		{name: "report keyed by language", report: report, targetID: "my-typescript", lang: "typescript", want: "minor"},
+		{name: "report keyed by target", report: report, targetID: "my-python", lang: "python", want: "patch"},
+		{name: "manual bump", report: report, manualBump: &manual, targetID: "my-go", lang: "go", want: "major"},
+		{name: "unknown", report: nil, targetID: "my-go", lang: "go", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, bumpTypeForTarget(tt.report, tt.manualBump, tt.targetID, tt.lang))
+		})
+	}
+}
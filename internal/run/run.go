@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v63/github"
 	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
@@ -18,6 +19,8 @@ import (
 	config "github.com/speakeasy-api/sdk-gen-config"
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 )
 
 type LanguageGenInfo struct {
@@ -42,22 +45,26 @@ type RunResult struct {
 	VersioningInfo       versionbumps.VersioningInfo
 	// key is language, value is release notes
 	ReleaseNotes map[string]string
+	// Targets maps target IDs to their results, for clis reporting them
+	Targets  map[string]cli.TargetResult
+	Duration time.Duration
+	Warnings []string
 }
 
 type Git interface {
 	CheckDirDirty(dir string, ignoreMap map[string]string) (bool, string, error)
 }
 
-func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[string]string, error) {
+func Run(ctx context.Context, g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[string]string, error) {
 	workspace := environment.GetWorkspace()
 	outputs := map[string]string{}
 	releaseNotes := map[string]string{}
 
-	executeSpeakeasyVersion, err := cli.GetSpeakeasyVersion()
+	executeSpeakeasyVersion, err := cli.GetSpeakeasyVersion(ctx)
 	if err != nil {
 		return nil, outputs, fmt.Errorf("failed to get speakeasy version: %w", err)
 	}
-	executeGenerationVersion, err := cli.GetGenerationVersion()
+	executeGenerationVersion, err := cli.GetGenerationVersion(ctx)
 	if err != nil {
 		return nil, outputs, fmt.Errorf("failed to get generation version: %w", err)
 	}
@@ -72,13 +79,13 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 
 	installationURLs := map[string]string{}
 	repoURL := getRepoURL()
-	fmt.Println("INPUT_ENABLE_SDK_CHANGELOG: ", environment.GetSDKChangelog())
+	logging.Info("INPUT_ENABLE_SDK_CHANGELOG: %s", environment.GetSDKChangelog())
 	repoSubdirectories := map[string]string{}
 	previousManagementInfos := map[string]config.Management{}
 
 	var manualVersioningBump *versioning.BumpType
 	if versionBump := versionbumps.GetLabelBasedVersionBump(pr); versionBump != "" && versionBump != versioning.BumpNone {
-		fmt.Println("Using label based version bump: ", versionBump)
+		logging.Info("Using label based version bump: %s", versionBump)
 		manualVersioningBump = &versionBump
 	}
 
@@ -115,7 +122,7 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 			return nil, outputs, err
 		}
 
-		fmt.Printf("Generating %s SDK in %s\n", lang, outputDir)
+		logging.With("target", lang, "dir", outputDir).Info("Generating %s SDK", lang)
 
 		installationURL := getInstallationURL(lang, dir)
 
@@ -138,19 +145,57 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 	var runRes *cli.RunResults
 	var changereport *versioning.MergedVersionReport
 
-	changereport, runRes, err = versioning.WithVersionReportCapture[*cli.RunResults](context.Background(), func(ctx context.Context) (*cli.RunResults, error) {
-		return cli.Run(wf.Targets == nil || len(wf.Targets) == 0, installationURLs, repoURL, repoSubdirectories, manualVersioningBump)
+	endGroup := logging.Group("Run Speakeasy workflow")
+	changereport, runRes, err = versioning.WithVersionReportCapture[*cli.RunResults](ctx, func(ctx context.Context) (*cli.RunResults, error) {
+		return cli.Run(ctx, wf.Targets == nil || len(wf.Targets) == 0, installationURLs, repoURL, repoSubdirectories, manualVersioningBump)
 	})
+	endGroup()
+	if runRes != nil {
+		for _, warning := range runRes.Warnings {
+			summary.AddWarning(warning)
+		}
+	}
 	if err != nil {
-		return nil, outputs, err
+		if runRes == nil {
+			return nil, outputs, err
+		}
+
+		// Report the targets the cli got to before failing
+		for targetID, target := range wf.Targets {
+			if environment.SpecifiedTarget() != "" && environment.SpecifiedTarget() != "all" && environment.SpecifiedTarget() != targetID {
+				continue
+			}
+
+			dir, _ := getDirAndOutputDir(target)
+			summary.AddTarget(withTargetResult(summary.Target{
+				Name:              targetID,
+				Directory:         dir,
+				PreviousVersion:   previousManagementInfos[targetID].ReleaseVersion,
+				Version:           previousManagementInfos[targetID].ReleaseVersion,
+				PublishingEnabled: outputs[utils.OutputTargetPublish(target.Target)] == "true",
+			}, runRes, targetID))
+		}
+
+		return &RunResult{
+			OpenAPIChangeSummary: runRes.OpenAPIChangeSummary,
+			LintingReportURL:     runRes.LintingReportURL,
+			ChangesReportURL:     runRes.ChangesReportURL,
+			Targets:              runRes.Targets,
+			Duration:             runRes.Duration,
+			Warnings:             runRes.Warnings,
+		}, outputs, err
 	}
 	if len(changereport.Reports) == 0 {
 		// Assume it's not yet enabled (e.g. CLI version too old)
+		if cli.GetCapabilities().Has(cli.FeatureVersionReports) {
+			logging.Debug("No version reports were captured although the CLI supports them")
+		}
 		changereport = nil
 	}
 	if changereport != nil && !changereport.MustGenerate() && !environment.ForceGeneration() && pr == nil {
 		// no further steps
-		fmt.Printf("No changes that imply the need for us to automatically regenerate the SDK.\n  Use \"Force Generation\" if you want to force a new generation.\n  Changes would include:\n-----\n%s", changereport.GetMarkdownSection())
+		summary.SetNotRegeneratedReason("No changes imply the need to regenerate the SDK. Use \"Force Generation\" to force a new generation. Changes would include:\n\n" + changereport.GetMarkdownSection())
+		logging.Info("No changes that imply the need for us to automatically regenerate the SDK.\n  Use \"Force Generation\" if you want to force a new generation.\n  Changes would include:\n-----\n%s", changereport.GetMarkdownSection())
 		return &RunResult{
 			GenInfo: nil,
 			VersioningInfo: versionbumps.VersioningInfo{
@@ -160,12 +205,15 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 			OpenAPIChangeSummary: runRes.OpenAPIChangeSummary,
 			LintingReportURL:     runRes.LintingReportURL,
 			ChangesReportURL:     runRes.ChangesReportURL,
+			Targets:              runRes.Targets,
+			Duration:             runRes.Duration,
+			Warnings:             runRes.Warnings,
 		}, outputs, nil
 	}
 
 	// For terraform, we also trigger "go generate ./..." to regenerate docs
 	if includesTerraform {
-		if err = cli.TriggerGoGenerate(); err != nil {
+		if err = cli.TriggerGoGenerate(ctx); err != nil {
 			return nil, outputs, err
 		}
 	}
@@ -221,10 +269,24 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 				generationVersion = currentManagementInfo.GenerationVersion
 			}
 
-			fmt.Printf("Regenerating %s SDK resulted in significant changes %s\n", lang, dirtyMsg)
+			logging.With("target", lang).Info("Regenerating %s SDK resulted in significant changes %s", lang, dirtyMsg)
 		} else {
-			fmt.Printf("Regenerating %s SDK did not result in any changes\n", lang)
+			logging.With("target", lang).Info("Regenerating %s SDK did not result in any changes", lang)
+		}
+
+		targetSummary := summary.Target{
+			Name:              targetID,
+			Directory:         dir,
+			Regenerated:       dirty,
+			PreviousVersion:   previousManagementInfo.ReleaseVersion,
+			Version:           previousManagementInfo.ReleaseVersion,
+			PublishingEnabled: outputs[utils.OutputTargetPublish(lang)] == "true",
+		}
+		if dirty {
+			targetSummary.Version = currentManagementInfo.ReleaseVersion
+			targetSummary.BumpType = bumpTypeForTarget(changereport, manualVersioningBump, targetID, lang)
 		}
+		summary.AddTarget(withTargetResult(targetSummary, runRes, targetID))
 	}
 
 	outputs["previous_gen_version"] = globalPreviousGenVersion
@@ -268,9 +330,47 @@ func Run(g Git, pr *github.PullRequest, wf *workflow.Workflow) (*RunResult, map[
 		LintingReportURL:     runRes.LintingReportURL,
 		ChangesReportURL:     runRes.ChangesReportURL,
 		ReleaseNotes:         releaseNotes,
+		Targets:              runRes.Targets,
+		Duration:             runRes.Duration,
+		Warnings:             runRes.Warnings,
 	}, outputs, nil
 }
 
+// withTargetResult adds the result of the target reported by the cli, if any,
+// to its summary.
+func withTargetResult(t summary.Target, runRes *cli.RunResults, targetID string) summary.Target {
+	result, ok := runRes.Targets[targetID]
+	if !ok {
+		return t
+	}
+
+	t.Status = string(result.Status)
+	t.Duration = result.Duration
+	t.Warnings = result.Warnings
+	t.Error = result.Error
+
+	return t
+}
+
+// bumpTypeForTarget returns the type of the version bump of the target, from
+// its version report, keyed by target or language, or the bump requested by
+// label.
+func bumpTypeForTarget(report *versioning.MergedVersionReport, manualBump *versioning.BumpType, targetID, lang string) string {
+	if report != nil {
+		for _, r := range report.Reports {
+			if r.Key != "" && (r.Key == targetID || r.Key == lang) && r.BumpType != "" {
+				return string(r.BumpType)
+			}
+		}
+	}
+
+	if manualBump != nil {
+		return string(*manualBump)
+	}
+
+	return ""
+}
+
 func getPreviousGenVersion(lockFile *config.LockFile, lang, globalPreviousGenVersion string) (string, error) {
 	previousFeatureVersions, ok := lockFile.Features[lang]
 	if !ok {
@@ -297,9 +397,16 @@ func getPreviousGenVersion(lockFile *config.LockFile, lang, globalPreviousGenVer
 func getInstallationURL(lang, subdirectory string) string {
 	subdirectory = filepath.Clean(subdirectory)
 
+	repo := environment.GetRepo()
+	// Mirrored targets are installed from the root of their mirror repository
+	if mirror := environment.GetMirrorRepository(subdirectory); mirror != "" {
+		repo = mirror
+		subdirectory = "."
+	}
+
 	switch lang {
 	case "cli", "go":
-		base := fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), environment.GetRepo())
+		base := fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), repo)
 
 		if subdirectory == "." {
 			return base
@@ -308,12 +415,12 @@ func getInstallationURL(lang, subdirectory string) string {
 		return base + "/" + subdirectory
 	case "typescript":
 		if subdirectory == "." {
-			return fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), environment.GetRepo())
+			return fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), repo)
 		} else {
 			return ""
 		}
 	case "python":
-		base := fmt.Sprintf("%s/%s.git", environment.GetGithubServerURL(), environment.GetRepo())
+		base := fmt.Sprintf("%s/%s.git", environment.GetGithubServerURL(), repo)
 
 		if subdirectory == "." {
 			return base
@@ -321,12 +428,17 @@ func getInstallationURL(lang, subdirectory string) string {
 
 		return base + "#subdirectory=" + subdirectory
 	case "php":
-		// PHP doesn't support subdirectories
+		// PHP doesn't support subdirectories, so these targets need a mirror repository
+		if subdirectory == "." {
+			return fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), repo)
+		}
+	case "swift":
+		// Swift Package Manager only resolves packages at the root of a repository
 		if subdirectory == "." {
-			return fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), environment.GetRepo())
+			return fmt.Sprintf("%s/%s.git", environment.GetGithubServerURL(), repo)
 		}
 	case "ruby":
-		base := fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), environment.GetRepo())
+		base := fmt.Sprintf("%s/%s", environment.GetGithubServerURL(), repo)
 
 		if subdirectory == "." {
 			return base
@@ -339,19 +451,46 @@ func getInstallationURL(lang, subdirectory string) string {
 	return ""
 }
 
+// targetDirectory returns the output directory of the target relative to the
+// root of the repository.
+func targetDirectory(target workflow.Target) string {
+	dir := "."
+	if target.Output != nil {
+		dir = *target.Output
+	}
+
+	return filepath.Clean(filepath.Join(environment.GetWorkingDirectory(), dir))
+}
+
 func getRepoURL() string {
 	return fmt.Sprintf("%s/%s.git", environment.GetGithubServerURL(), environment.GetRepo())
 }
 
 func AddTargetPublishOutputs(target workflow.Target, outputs map[string]string, installationURL *string) {
 	lang := target.Target
-	published := target.IsPublished() || lang == "go"
+	// Go and Swift packages are published by their GitHub release tags
+	published := target.IsPublished() || lang == "go" || lang == "swift"
 
 	// TODO: Temporary check to fix Java. We may remove this entirely, pending conversation
 	if installationURL != nil && *installationURL == "" && lang != "java" {
 		published = true // Treat as published if we don't have an installation URL
 	}
 
+	// Targets published to a private registry may not have a publishing config the workflow knows about
+	override, hasOverride := environment.GetRegistryOverride(lang)
+	if hasOverride && override.Publish != nil {
+		published = *override.Publish
+	}
+
+	// Swift Package Manager only resolves packages at the root of a repository,
+	// so swift targets in a subdirectory are only published through a mirror
+	if lang == "swift" && published {
+		if dir := targetDirectory(target); dir != "." && environment.GetMirrorRepository(dir) == "" {
+			published = false
+			outputs[utils.OutputTargetPublishSkippedReason(lang)] = fmt.Sprintf("swift target in %s has no mirror repository configured", dir)
+		}
+	}
+
 	// Use OR logic: don't let a non-published target overwrite a previously
 	// set published=true (e.g. workflow.local.yaml targets without publish blocks
 	// sharing the same output directory as the main target).
@@ -360,6 +499,10 @@ func AddTargetPublishOutputs(target workflow.Target, outputs map[string]string,
 		outputs[outputKey] = strconv.FormatBool(published)
 	}
 
+	if published && hasOverride && override.BaseURL != "" {
+		outputs[utils.OutputTargetRegistryURL(lang)] = override.BaseURL
+	}
+
 	if published && lang == "java" && target.Publishing != nil && target.Publishing.Java != nil {
 		outputs["use_sonatype_legacy"] = strconv.FormatBool(target.Publishing.Java.UseSonatypeLegacy)
 	}
@@ -371,7 +514,7 @@ func AddTargetPublishOutputs(target workflow.Target, outputs map[string]string,
 
 	if lang == "mcp-typescript" && target.Publishing != nil && target.Publishing.MCPRegistry != nil &&
 		target.Publishing.MCPRegistry.Auth != "" {
-		outputs["publish_mcp_registry"] = "true"
+		outputs[utils.OutputPublishMCPRegistry] = "true"
 		outputs["mcp_registry_auth"] = target.Publishing.MCPRegistry.Auth
 	}
 }
@@ -51,6 +51,15 @@ func TestAddTargetPublishOutputs(t *testing.T) {
 				"publish_go": "true",
 			},
 		},
+		"swift-publishing": {
+			target: workflow.Target{
+				Publishing: nil, // intentionally no publishing config, Swift does not use
+				Target:     "swift",
+			},
+			expectedOutputs: map[string]string{
+				"publish_swift": "true",
+			},
+		},
 		"cli-no-publishing": {
 			target: workflow.Target{
 				Publishing: nil, // intentionally no publishing config
@@ -307,3 +316,69 @@ func TestAddTargetPublishOutputs_NoOverwritePublished(t *testing.T) {
 		t.Errorf("expected %v, got %v (reverse order)", expected, outputs2)
 	}
 }
+
+func TestAddTargetPublishOutputs_RegistryOverride(t *testing.T) {
+	t.Setenv("INPUT_REGISTRY_OVERRIDES", `{
+		"typescript": {"name": "github-packages", "base_url": "https://npm.pkg.github.com/", "publish": true},
+		"python": {"publish": false}
+	}`)
+
+	testCases := map[string]struct {
+		target          workflow.Target
+		expectedOutputs map[string]string
+	}{
+		"private registry without publishing config": {
+			target: workflow.Target{Target: "typescript"},
+			expectedOutputs: map[string]string{
+				"publish_typescript":      "true",
+				"typescript_registry_url": "https://npm.pkg.github.com",
+			},
+		},
+		"publishing disabled": {
+			target: workflow.Target{
+				Publishing: &workflow.Publishing{
+					PyPi: &workflow.PyPi{
+						Token: "non-empty",
+					},
+				},
+				Target: "python",
+			},
+			expectedOutputs: map[string]string{
+				"publish_python": "false",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gotOutputs := make(map[string]string)
+			run.AddTargetPublishOutputs(tc.target, gotOutputs, nil)
+
+			if !reflect.DeepEqual(gotOutputs, tc.expectedOutputs) {
+				t.Errorf("expected %v, got %v", tc.expectedOutputs, gotOutputs)
+			}
+		})
+	}
+}
+
+func TestAddTargetPublishOutputs_SwiftSubdirectory(t *testing.T) {
+	t.Setenv("INPUT_WORKING_DIRECTORY", "")
+	t.Setenv("INPUT_MIRROR_REPOSITORIES", "sdks/mirrored=org/swift-sdk")
+
+	outputs := map[string]string{}
+	run.AddTargetPublishOutputs(workflow.Target{Target: "swift", Output: ptr("sdks/swift")}, outputs, nil)
+	expected := map[string]string{
+		"publish_swift":                "false",
+		"swift_publish_skipped_reason": "swift target in sdks/swift has no mirror repository configured",
+	}
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("expected %v, got %v", expected, outputs)
+	}
+
+	outputs = map[string]string{}
+	run.AddTargetPublishOutputs(workflow.Target{Target: "swift", Output: ptr("sdks/mirrored")}, outputs, nil)
+	expected = map[string]string{"publish_swift": "true"}
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("expected %v, got %v", expected, outputs)
+	}
+}
@@ -0,0 +1,29 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTargetResult(t *testing.T) {
+	runRes := &cli.RunResults{
+		Targets: map[string]cli.TargetResult{
+			"my-python": {Status: cli.TargetStatusFailed, Duration: time.Second, Warnings: []string{"unused schema"}, Error: "compilation failed"},
+		},
+	}
+
+	assert.Equal(t, summary.Target{
+		Name:     "my-python",
+		Status:   "failed",
+		Duration: time.Second,
+		Warnings: []string{"unused schema"},
+		Error:    "compilation failed",
+	}, withTargetResult(summary.Target{Name: "my-python"}, runRes, "my-python"))
+
+	// Older clis don't report target results
+	assert.Equal(t, summary.Target{Name: "my-go"}, withTargetResult(summary.Target{Name: "my-go"}, runRes, "my-go"))
+}
@@ -1,6 +1,7 @@
 package suggestions
 
 import (
+	"context"
 	"fmt"
 	"github.com/speakeasy-api/sdk-generation-action/internal/cli"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
@@ -24,8 +25,8 @@ type prCommentsInfo struct {
 	lineNums     []int
 }
 
-func Suggest(docPath, maxSuggestions string) (string, error) {
-	out, err := cli.Suggest(docPath, maxSuggestions, environment.GetOpenAPIDocOutput())
+func Suggest(ctx context.Context, docPath, maxSuggestions string) (string, error) {
+	out, err := cli.Suggest(ctx, docPath, maxSuggestions, environment.GetOpenAPIDocOutput())
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,342 @@
+// Package summary collects the results of an action run and writes them as a
+// Markdown job summary, shown on the workflow run page.
+package summary
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+)
+
+type Target struct {
+	Name            string
+	Directory       string
+	Regenerated     bool
+	PreviousVersion string
+	Version         string
+	BumpType        string
+	// PublishingEnabled reports whether the publishing jobs publish the target,
+	// which happens after the run.
+	PublishingEnabled bool
+	// Status, Duration, Warnings and Error are reported by clis writing run
+	// results.
+	Status   string
+	Duration time.Duration
+	Warnings []string
+	Error    string
+}
+
+type Link struct {
+	Title string
+	URL   string
+}
+
+type Release struct {
+	Target string
+	Tag    string
+	Status string
+	URL    string
+	Error  string
+}
+
+type TagPromotion struct {
+	Tags        []string
+	Sources     []string
+	CodeSamples []string
+}
+
+type TestResult struct {
+	Target    string
+	Passed    bool
+	ReportURL string
+}
+
+// Summary is the report of an action run.
+type Summary struct {
+	mu sync.Mutex
+
+	Targets []Target
+	Links   []Link
+	// NotRegeneratedReason explains, as Markdown, why no target was regenerated
+	NotRegeneratedReason string
+	Releases             []Release
+	TagPromotions        []TagPromotion
+	Tests                []TestResult
+	// Warnings are reported by the run rather than by a target
+	Warnings []string
+}
+
+var current = &Summary{}
+
+func AddTarget(target Target) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.Targets = append(current.Targets, target)
+}
+
+// AddLink adds a link to the summary, ignoring empty URLs.
+func AddLink(title, url string) {
+	if url == "" {
+		return
+	}
+
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.Links = append(current.Links, Link{Title: title, URL: url})
+}
+
+// AddCommitLink adds a link to the commit in the repository being generated.
+func AddCommitLink(commitHash string) {
+	if commitHash == "" || environment.GetGithubServerURL() == "" {
+		return
+	}
+
+	title := "Commit " + commitHash
+	if len(commitHash) > 7 {
+		title = "Commit " + commitHash[:7]
+	}
+
+	AddLink(title, fmt.Sprintf("%s/%s/commit/%s", environment.GetGithubServerURL(), environment.GetRepo(), commitHash))
+}
+
+// AddPullRequestLink adds a link to the pull request in the repository being
+// generated.
+func AddPullRequestLink(number int) {
+	if environment.GetGithubServerURL() == "" {
+		return
+	}
+
+	AddLink(fmt.Sprintf("Pull request #%d", number), fmt.Sprintf("%s/%s/pull/%d", environment.GetGithubServerURL(), environment.GetRepo(), number))
+}
+
+func AddWarning(warning string) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.Warnings = append(current.Warnings, warning)
+}
+
+func SetNotRegeneratedReason(reason string) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	if current.NotRegeneratedReason == "" {
+		current.NotRegeneratedReason = reason
+	}
+}
+
+func AddRelease(release Release) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.Releases = append(current.Releases, release)
+}
+
+func AddTagPromotion(promotion TagPromotion) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.TagPromotions = append(current.TagPromotions, promotion)
+}
+
+func AddTestResult(result TestResult) {
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	current.Tests = append(current.Tests, result)
+}
+
+// Write appends the summary of the action's run to the job summary file. It
+// does nothing outside of GitHub Actions.
+func Write(action environment.Action, runErr error) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening step summary file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(current.Markdown(action, runErr)); err != nil {
+		return fmt.Errorf("error writing step summary: %w", err)
+	}
+
+	return nil
+}
+
+// Markdown renders the summary of a run of the action.
+func (s *Summary) Markdown(action environment.Action, runErr error) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+
+	status := "✅"
+	if runErr != nil {
+		status = "❌"
+	}
+	fmt.Fprintf(&b, "## %s Speakeasy %s\n\n", status, action)
+
+	if runErr != nil {
+		msg := strings.TrimSpace(redact.String(runErr.Error()))
+		f := fence(msg)
+		fmt.Fprintf(&b, "%s\n%s\n%s\n\n", f, msg, f)
+	}
+
+	if len(s.Targets) > 0 {
+		b.WriteString("### Targets\n\n")
+		b.WriteString("| Target | Directory | Status | Regenerated | Previous version | Version | Bump | Duration | Publishing enabled |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, t := range s.Targets {
+			status := cell(t.Status)
+			if t.Error != "" {
+				status += ": " + cell(t.Error)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+				cell(t.Name), cell(t.Directory), status, yesNo(t.Regenerated), cell(t.PreviousVersion), cell(t.Version), cell(t.BumpType), duration(t.Duration), yesNo(t.PublishingEnabled))
+		}
+		b.WriteString("\n")
+	}
+
+	var hasTargetWarnings bool
+	for _, t := range s.Targets {
+		hasTargetWarnings = hasTargetWarnings || len(t.Warnings) > 0
+	}
+	if len(s.Warnings) > 0 || hasTargetWarnings {
+		b.WriteString("### Warnings\n\n")
+		for _, w := range s.Warnings {
+			fmt.Fprintf(&b, "- %s\n", cell(w))
+		}
+		for _, t := range s.Targets {
+			for _, w := range t.Warnings {
+				fmt.Fprintf(&b, "- %s: %s\n", code([]string{t.Name}), cell(w))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if s.NotRegeneratedReason != "" {
+		b.WriteString("### Nothing was regenerated\n\n")
+		b.WriteString(strings.TrimSpace(s.NotRegeneratedReason) + "\n\n")
+	}
+
+	if len(s.Links) > 0 {
+		b.WriteString("### Links\n\n")
+		for _, l := range s.Links {
+			fmt.Fprintf(&b, "- [%s](%s)\n", l.Title, l.URL)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.Releases) > 0 {
+		b.WriteString("### Releases\n\n")
+		b.WriteString("| Target | Tag | Status |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, r := range s.Releases {
+			tag := cell(r.Tag)
+			if r.URL != "" {
+				tag = fmt.Sprintf("[%s](%s)", tag, r.URL)
+			}
+			status := cell(r.Status)
+			if r.Error != "" {
+				status += ": " + cell(r.Error)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(r.Target), tag, status)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.TagPromotions) > 0 {
+		b.WriteString("### Registry tags promoted\n\n")
+		for _, p := range s.TagPromotions {
+			fmt.Fprintf(&b, "- %s", code(p.Tags))
+			if len(p.Sources) > 0 {
+				fmt.Fprintf(&b, " on sources %s", code(p.Sources))
+			}
+			if len(p.CodeSamples) > 0 {
+				fmt.Fprintf(&b, " on code samples %s", code(p.CodeSamples))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(s.Tests) > 0 {
+		b.WriteString("### Tests\n\n")
+		b.WriteString("| Target | Result | Report |\n")
+		b.WriteString("| --- | --- | --- |\n")
+		for _, t := range s.Tests {
+			result := "✅ passed"
+			if !t.Passed {
+				result = "❌ failed"
+			}
+			report := ""
+			if t.ReportURL != "" {
+				report = fmt.Sprintf("[View report](%s)", t.ReportURL)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(t.Target), result, report)
+		}
+		b.WriteString("\n")
+	}
+
+	return redact.String(b.String())
+}
+
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// fence returns a code fence longer than any run of backticks in s, so that s
+// can't close the code block it is rendered in.
+func fence(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+func duration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	return d.Round(time.Second).String()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
+
+func code(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+
+	return strings.Join(quoted, ", ")
+}
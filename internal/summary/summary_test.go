@@ -0,0 +1,106 @@
+package summary
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdown(t *testing.T) {
+	t.Cleanup(redact.Load)
+	t.Setenv("INPUT_GITHUB_ACCESS_TOKEN", "ghp_token123")
+	redact.Load()
+
+	s := &Summary{
+		Targets: []Target{
+			{Name: "my-sdk", Directory: "sdks/ts", Regenerated: true, PreviousVersion: "1.2.0", Version: "1.3.0", BumpType: "minor", PublishingEnabled: true, Status: "generated", Duration: 3200 * time.Millisecond, Warnings: []string{"unused schema"}},
+			{Name: "my|py", Directory: ".", PreviousVersion: "0.4.0", Version: "0.4.0", Status: "failed", Error: "compilation failed"},
+		},
+		Warnings: []string{"deprecated option"},
+		Links:    []Link{{Title: "Changes report", URL: "https://app.speakeasy.com/changes"}},
+		Releases: []Release{{Target: "typescript", Tag: "v1.3.0", Status: "created", URL: "https://github.com/org/repo/releases/tag/v1.3.0"}, {Target: "go", Tag: "v0.1.0", Status: "failed", Error: "tag exists"}},
+		TagPromotions: []TagPromotion{
+			{Tags: []string{"main", "published"}, Sources: []string{"api"}, CodeSamples: []string{"my-sdk"}},
+		},
+		Tests: []TestResult{{Target: "my-sdk", Passed: true, ReportURL: "https://app.speakeasy.com/tests"}, {Target: "other", Passed: false}},
+	}
+
+	want := "## ❌ Speakeasy run-workflow\n\n" +
+		"```\nfailed to push with ***\n```\n\n" +
+		"### Targets\n\n" +
+		"| Target | Directory | Status | Regenerated | Previous version | Version | Bump | Duration | Publishing enabled |\n" +
+		"| --- | --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+		"| my-sdk | sdks/ts | generated | yes | 1.2.0 | 1.3.0 | minor | 3s | yes |\n" +
+		"| my\\|py | . | failed: compilation failed | no | 0.4.0 | 0.4.0 |  |  | no |\n\n" +
+		"### Warnings\n\n" +
+		"- deprecated option\n" +
+		"- `my-sdk`: unused schema\n\n" +
+		"### Links\n\n" +
+		"- [Changes report](https://app.speakeasy.com/changes)\n\n" +
+		"### Releases\n\n" +
+		"| Target | Tag | Status |\n" +
+		"| --- | --- | --- |\n" +
+		"| typescript | [v1.3.0](https://github.com/org/repo/releases/tag/v1.3.0) | created |\n" +
+		"| go | v0.1.0 | failed: tag exists |\n\n" +
+		"### Registry tags promoted\n\n" +
+		"- `main`, `published` on sources `api` on code samples `my-sdk`\n\n" +
+		"### Tests\n\n" +
+		"| Target | Result | Report |\n" +
+		"| --- | --- | --- |\n" +
+		"| my-sdk | ✅ passed | [View report](https://app.speakeasy.com/tests) |\n" +
+		"| other | ❌ failed |  |\n\n"
+
+	assert.Equal(t, want, s.Markdown(environment.ActionRunWorkflow, errors.New("failed to push with ghp_token123")))
+}
+
+func TestMarkdown_ErrorWithCodeFence(t *testing.T) {
+	s := &Summary{}
+
+	got := s.Markdown(environment.ActionRunWorkflow, errors.New("validation failed:\n```\nbad spec\n````"))
+
+	assert.Equal(t, "## ❌ Speakeasy run-workflow\n\n`````\nvalidation failed:\n```\nbad spec\n````\n`````\n\n", got)
+}
+
+func TestMarkdown_NotRegenerated(t *testing.T) {
+	s := &Summary{NotRegeneratedReason: "No changes imply the need to regenerate the SDK.\n"}
+
+	assert.Equal(t, "## ✅ Speakeasy run-workflow\n\n### Nothing was regenerated\n\nNo changes imply the need to regenerate the SDK.\n\n", s.Markdown(environment.ActionRunWorkflow, nil))
+}
+
+func TestWrite(t *testing.T) {
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "org/repo")
+	t.Setenv("INPUT_GITHUB_REPOSITORY", "")
+
+	previous := current
+	current = &Summary{}
+	t.Cleanup(func() { current = previous })
+
+	AddCommitLink("0123456789abcdef")
+	AddPullRequestLink(12)
+	AddLink("Linting report", "")
+	SetNotRegeneratedReason("first")
+	SetNotRegeneratedReason("second")
+
+	path := filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o600))
+	t.Setenv("GITHUB_STEP_SUMMARY", path)
+
+	require.NoError(t, Write(environment.ActionRelease, nil))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "existing\n## ✅ Speakeasy release\n\n### Nothing was regenerated\n\nfirst\n\n### Links\n\n"+
+		"- [Commit 0123456](https://github.com/org/repo/commit/0123456789abcdef)\n"+
+		"- [Pull request #12](https://github.com/org/repo/pull/12)\n\n", string(data))
+
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	assert.NoError(t, Write(environment.ActionRelease, nil))
+}
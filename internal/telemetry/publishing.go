@@ -3,23 +3,29 @@ package telemetry
 import (
 	"context"
 	"fmt"
-	"os"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	config "github.com/speakeasy-api/sdk-gen-config"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
-	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
 	"github.com/speakeasy-api/speakeasy-client-sdk-go/v3/pkg/models/shared"
 )
 
-func TriggerPublishingEvent(targetDirectory, result, registryName string) (string, error) {
+// TriggerPublishingEvent records the publishing of the target in the directory
+// to the registry, returning the version published and the target it was
+// published from, if known. The target is looked up from the languages
+// generated in the directory if not given.
+func TriggerPublishingEvent(targetDirectory, result, registryName, target string) (string, string, error) {
 	workspace := environment.GetWorkspace()
 	path := filepath.Join(workspace, "repo")
 	path = filepath.Join(path, targetDirectory)
 
 	var packageVersion string
-	return packageVersion, Track(context.Background(), shared.InteractionTypePublish, func(ctx context.Context, event *shared.CliEvent) error {
+	err := Track(context.Background(), shared.InteractionTypePublish, func(ctx context.Context, event *shared.CliEvent) error {
 		if registryName != "" {
 			event.PublishPackageRegistryName = &registryName
 		}
@@ -30,263 +36,90 @@ func TriggerPublishingEvent(targetDirectory, result, registryName string) (strin
 		}
 
 		if loadedCfg.LockFile == nil {
-			return fmt.Errorf("empty lock file for python language target in directory %s", path)
+			return fmt.Errorf("empty lock file for target in directory %s", path)
 		}
 
 		version := processLockFile(*loadedCfg.LockFile, event)
 		packageVersion = version
 
-		var processingErr error
-		switch registryName {
-		case "pypi":
-			processingErr = processPyPI(loadedCfg, event, path, version)
-		case "npm":
-			processingErr = processNPM(loadedCfg, event, path, version)
-		case "packagist":
-			processingErr = processPackagist(loadedCfg, event, path)
-		case "nuget":
-			processingErr = processNuget(loadedCfg, event, path, version)
-		case "gems":
-			processingErr = processGems(loadedCfg, event, path, version)
-		case "sonatype":
-			processingErr = processSonatype(loadedCfg, event, path, version)
-		case "terraform":
-			processingErr = processTerraform(loadedCfg, event, path, version)
-		case "go":
-			processingErr = processGo(loadedCfg, event, path, version)
+		if err := processPublishedPackage(loadedCfg, event, path, registryName, target, version); err != nil {
+			return err
 		}
-
-		if processingErr != nil {
-			return processingErr
+		if event.GenerateTarget != nil {
+			target = *event.GenerateTarget
 		}
 
 		event.Success = strings.Contains(strings.ToLower(result), "success")
 
 		return nil
 	})
-}
 
-func processPyPI(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "python"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
-	}
-
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
-	}
-
-	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && version != "" {
-		publishURL := fmt.Sprintf("https://pypi.org/project/%s/%s", packageName, version)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
+	return packageVersion, target, err
 }
 
-func processNPM(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "typescript"
+// processPublishedPackage records the target, package name and package URL of
+// the package published to the registry from the target in path.
+func processPublishedPackage(cfg *config.Config, event *shared.CliEvent, path, registryName, target, version string) error {
 	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
+		return fmt.Errorf("empty config for target in directory %s", path)
 	}
 
-	langCfg, ok := cfg.Config.Languages[lang]
+	registry, ok := publishtargets.ForPublishEvent(registryName, generatedTargets(cfg, target))
 	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
-	}
-
-	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && version != "" {
-		publishURL := fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", packageName, version)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
-}
-
-func processGo(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "go"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
+		return fmt.Errorf("no single target published to %s found in directory %s", registryName, path)
 	}
 
+	lang := registry.Target
 	langCfg, ok := cfg.Config.Languages[lang]
 	if !ok {
 		return fmt.Errorf("no %s config in directory %s", lang, path)
 	}
 
 	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && version != "" {
-		relPath, err := filepath.Rel(filepath.Join(environment.GetWorkspace(), "repo"), path)
-		if err != nil {
-			return err
-		}
-
-		tag := fmt.Sprintf("v%s", version)
-		if relPath != "" && relPath != "." && relPath != "./" {
-			tag = fmt.Sprintf("%s/%s", relPath, tag)
-		}
-
-		publishURL := fmt.Sprintf("https://github.com/%s/releases/tag/%s", os.Getenv("GITHUB_REPOSITORY"), tag)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
-}
-
-func processPackagist(cfg *config.Config, event *shared.CliEvent, path string) error {
-	lang := "php"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
-	}
-
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
+	if event.PublishPackageRegistryName == nil {
+		event.PublishPackageRegistryName = &registry.Name
 	}
 
-	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
+	packageName := registry.PackageName(langCfg.Cfg)
 	event.PublishPackageName = &packageName
 
-	if packageName != "" {
-		publishURL := fmt.Sprintf("https://packagist.org/packages/%s", packageName)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
-}
-
-func processNuget(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "csharp"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
+	relPath, err := filepath.Rel(filepath.Join(environment.GetWorkspace(), "repo"), path)
+	if err != nil {
+		return err
 	}
 
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
-	}
-
-	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && version != "" {
-		publishURL := fmt.Sprintf("https://www.nuget.org/packages/%s/%s", packageName, version)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
-}
-
-func processGems(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "ruby"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
-	}
-
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
-	}
-
-	event.GenerateTarget = &lang
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && version != "" {
-		publishURL := fmt.Sprintf("https://rubygems.org/gems/%s/%s", packageName, version)
-		event.PublishPackageURL = &publishURL
-	}
-
-	return nil
-}
-
-func processSonatype(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "java"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
-	}
-
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
-	}
-
-	event.GenerateTarget = &lang
-
-	var groupID string
-	if name, ok := langCfg.Cfg["groupID"]; ok {
-		if strName, ok := name.(string); ok {
-			groupID = strName
-		}
-	}
-
-	var artifactID string
-	if name, ok := langCfg.Cfg["artifactID"]; ok {
-		if strName, ok := name.(string); ok {
-			artifactID = strName
-		}
-	}
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if groupID != "" && artifactID != "" && version != "" {
-		publishURL := fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, version)
+	publishURL := registry.PackageURL(publishtargets.Package{
+		Name:    packageName,
+		Version: version,
+		Path:    releases.PackagePath(lang, relPath),
+	})
+	if publishURL != "" {
 		event.PublishPackageURL = &publishURL
 	}
 
 	return nil
 }
 
-func processTerraform(cfg *config.Config, event *shared.CliEvent, path string, version string) error {
-	lang := "terraform"
-	if cfg.Config == nil {
-		return fmt.Errorf("empty config for %s language target in directory %s", lang, path)
-	}
-
-	langCfg, ok := cfg.Config.Languages[lang]
-	if !ok {
-		return fmt.Errorf("no %s config in directory %s", lang, path)
+// generatedTargets returns the targets the package may have been published
+// from: the given target, or else the languages configured in the directory
+// that the lockfile records were generated there.
+func generatedTargets(cfg *config.Config, target string) []string {
+	if target != "" {
+		return []string{target}
 	}
 
-	event.GenerateTarget = &lang
-
-	var author string
-	if name, ok := langCfg.Cfg["author"]; ok {
-		if strName, ok := name.(string); ok {
-			author = strName
+	var targets []string
+	for lang := range cfg.Config.Languages {
+		if _, ok := cfg.LockFile.Features[lang]; ok {
+			targets = append(targets, lang)
 		}
 	}
-
-	packageName := utils.GetPackageName(lang, &langCfg)
-	event.PublishPackageName = &packageName
-
-	if packageName != "" && author != "" && version != "" {
-		publishURL := fmt.Sprintf("https://registry.terraform.io/providers/%s/%s/%s", author, packageName, version)
-		event.PublishPackageURL = &publishURL
+	if len(targets) == 0 {
+		targets = slices.Collect(maps.Keys(cfg.Config.Languages))
 	}
+	slices.Sort(targets)
 
-	return nil
+	return targets
 }
 
 func processLockFile(lockFile config.LockFile, event *shared.CliEvent) string {
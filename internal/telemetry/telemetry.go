@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 	speakeasy "github.com/speakeasy-api/speakeasy-client-sdk-go/v3"
 	"github.com/speakeasy-api/speakeasy-client-sdk-go/v3/pkg/models/operations"
 	"github.com/speakeasy-api/speakeasy-client-sdk-go/v3/pkg/models/shared"
@@ -135,7 +136,7 @@ func Track(ctx context.Context, exec shared.InteractionType, fn func(ctx context
 	if environment.GetGithubOIDCRequestURL() != "" && environment.GetGithubOIDCRequestToken() != "" {
 		go func() {
 			if oidcToken, err := getIDToken(environment.GetGithubOIDCRequestURL(), environment.GetGithubOIDCRequestToken()); err != nil {
-				fmt.Println("Failed to get OIDC token", err)
+				logging.Info("Failed to get OIDC token: %v", err)
 			} else {
 				owner := os.Getenv("GITHUB_REPOSITORY_OWNER")
 				res, err := sdk.Github.LinkGithub(context.WithoutCancel(ctx), operations.LinkGithubAccessRequest{
@@ -143,11 +144,11 @@ func Track(ctx context.Context, exec shared.InteractionType, fn func(ctx context
 					GithubOrg:       &owner,
 				})
 				if err != nil {
-					fmt.Println("Failed to link github account", err)
+					logging.Info("Failed to link github account: %v", err)
 				}
 
 				if res != nil && res.StatusCode != 200 {
-					fmt.Println("Failed to link github account", err)
+					logging.With("status", res.StatusCode).Info("Failed to link github account")
 				}
 			}
 		}()
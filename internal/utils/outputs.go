@@ -20,6 +20,10 @@ func OutputTargetMCPRelease(targetName string) string {
 	return "mcp_release_" + targetName
 }
 
+// OutputPublishMCPRegistry is the output enabling publishing to the MCP
+// registry. It shares the publish_ prefix of the target outputs but isn't one.
+const OutputPublishMCPRegistry = "publish_mcp_registry"
+
 // Returns the publish output name for the given target name. This
 // automatically handles when the target name contains hyphens.
 func OutputTargetPublish(targetName string) string {
@@ -27,6 +31,27 @@ func OutputTargetPublish(targetName string) string {
 	return "publish_" + targetName
 }
 
+// Returns the output name explaining why publishing was skipped for the given
+// target name. This automatically handles when the target name contains hyphens.
+func OutputTargetPublishSkippedReason(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_publish_skipped_reason"
+}
+
+// Returns the registry URL output name for the given target name. This
+// automatically handles when the target name contains hyphens.
+func OutputTargetRegistryURL(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_registry_url"
+}
+
+// Returns the preview version output name for the given target name. This
+// automatically handles when the target name contains hyphens.
+func OutputTargetPreviewVersion(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_preview_version"
+}
+
 // Returns the regenerated output name for the given target name. This
 // automatically handles when the target name contains hyphens.
 func OutputTargetRegenerated(targetName string) string {
@@ -47,3 +72,24 @@ func OutputTargetGoReleaserPreviousTag(targetName string) string {
 	targetName = strings.ReplaceAll(targetName, "-", "_")
 	return targetName + "_goreleaser_previous_tag"
 }
+
+// Returns the release assets output name for the given target name. This
+// automatically handles when the target name contains hyphens.
+func OutputTargetReleaseAssets(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_release_assets"
+}
+
+// Returns the release status output name for the given target name. This
+// automatically handles when the target name contains hyphens.
+func OutputTargetReleaseStatus(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_release_status"
+}
+
+// Returns the mirror tag URL output name for the given target name. This
+// automatically handles when the target name contains hyphens.
+func OutputTargetMirrorURL(targetName string) string {
+	targetName = strings.ReplaceAll(targetName, "-", "_")
+	return targetName + "_mirror_url"
+}
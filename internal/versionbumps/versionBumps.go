@@ -7,6 +7,8 @@ import (
 	"github.com/google/go-github/v63/github"
 	"github.com/speakeasy-api/versioning-reports/versioning"
 	"golang.org/x/exp/slices"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
 )
 
 type BumpMethod string
@@ -50,7 +52,7 @@ func GetLabelBasedVersionBump(pr *github.PullRequest) versioning.BumpType {
 	if bumpType := stackRankBumpLabels(bumpLabels); bumpType != versioning.BumpNone {
 		currentPRBumpType, currentPRBumpMethod, err := parseBumpFromPRBody(pr.GetBody())
 		if err != nil {
-			fmt.Errorf("failed to parse bump type and mode from PR body: %w", err)
+			logging.Warn("failed to parse bump type and mode from PR body: %v", err)
 			return versioning.BumpNone
 		}
 
@@ -11,10 +11,19 @@ import (
 
 	"github.com/speakeasy-api/sdk-generation-action/internal/actions"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/process"
+	"github.com/speakeasy-api/sdk-generation-action/internal/redact"
+	"github.com/speakeasy-api/sdk-generation-action/internal/summary"
 	"golang.org/x/exp/slices"
 )
 
 func main() {
+	// Masked before anything is logged, so that secrets are also hidden from
+	// the output of later steps in the job
+	redact.Load()
+	logging.MaskSecrets()
+
 	if environment.IsDebugMode() {
 		envs := os.Environ()
 		slices.SortFunc(envs, func(i, j string) int {
@@ -31,39 +40,56 @@ func main() {
 		})
 
 		for _, env := range envs {
-			fmt.Println(env)
+			logging.Debug("%s", env)
 		}
 	}
 
+	// Commands are stopped on SIGTERM or SIGINT, so that the action can clean up
+	// after itself before exiting
+	ctx, stop := process.NotifyContext(context.Background())
+	defer stop()
+
 	var err error
 	// Don't fire CI_Exec telemetry on actions where we are only sending specific telemetry back.
 	if environment.GetAction() == environment.ActionLog {
 		err = actions.LogActionResult()
 	} else if environment.GetAction() == environment.ActionPublishEvent {
-		err = actions.PublishEventAction()
+		err = actions.PublishEventAction(ctx)
 	} else {
-		err = telemetry.Track(context.Background(), shared.InteractionTypeCiExec, func(ctx context.Context, event *shared.CliEvent) error {
+		err = telemetry.Track(ctx, shared.InteractionTypeCiExec, func(ctx context.Context, event *shared.CliEvent) error {
 			switch environment.GetAction() {
 			case environment.ActionSuggest:
-				return actions.Suggest()
+				return actions.Suggest(ctx)
 			case environment.ActionRunWorkflow:
-				return actions.RunWorkflow()
+				return actions.RunWorkflow(ctx)
 			case environment.ActionFinalizeSuggestion:
-				return actions.FinalizeSuggestion()
+				return actions.FinalizeSuggestion(ctx)
 			case environment.ActionRelease:
-				return actions.Release()
+				return actions.Release(ctx)
 			case environment.ActionTag:
-				return actions.Tag()
+				return actions.Tag(ctx)
 			case environment.ActionTest:
 				return actions.Test(ctx)
+			case environment.ActionRollback:
+				return actions.Rollback(ctx)
+			case environment.ActionHomebrewFormula:
+				return actions.HomebrewFormula(ctx)
+			case environment.ActionPreviewCleanup:
+				return actions.PreviewCleanup(ctx)
 			default:
 				return fmt.Errorf("unknown action: %s", environment.GetAction())
 			}
 		})
 	}
 
+	if environment.GetAction() != environment.ActionLog {
+		if err := summary.Write(environment.GetAction(), err); err != nil {
+			logging.Debug("failed to write step summary: %v", err)
+		}
+	}
+
 	if err != nil {
-		fmt.Printf("::error title=failed::%v\n", err)
+		logging.Annotation{Title: "failed"}.Error("%v", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,156 @@
+package releases
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
+)
+
+// GoModuleRelease describes how a release of a Go module must be tagged for
+// `go get` to resolve it.
+type GoModuleRelease struct {
+	ModulePath string
+	// ModuleDir is the directory containing the go.mod, relative to the root of
+	// the repository.
+	ModuleDir string
+	Tag       string
+}
+
+var (
+	majorVersionSuffixRegex = regexp.MustCompile(`/v([0-9]+)$`)
+	gopkgInSuffixRegex      = regexp.MustCompile(`\.v([0-9]+)(-unstable)?$`)
+)
+
+// PackagePath returns the path of the target at targetPath that its registry
+// derives release tags and package URLs from: the directory of the go.mod for
+// Go targets, found in the repository checkout, and the target path
+// otherwise.
+func PackagePath(lang, targetPath string) string {
+	if lang != "go" {
+		return targetPath
+	}
+
+	moduleDir, err := findGoModDir(filepath.Join(environment.GetWorkspace(), "repo"), targetPath)
+	if err != nil {
+		return targetPath
+	}
+
+	return moduleDir
+}
+
+// ValidateGoRelease reads the go.mod of the Go target at targetPath, or the
+// closest parent directory within repoDir, and checks that the module path's
+// major version suffix matches the release version. It returns the tag the
+// release must use.
+func ValidateGoRelease(repoDir, targetPath, releaseVersion string) (*GoModuleRelease, error) {
+	moduleDir, err := findGoModDir(repoDir, targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(repoDir, moduleDir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading go.mod: %w", err)
+	}
+
+	modulePath, err := parseModulePath(data)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := version.NewVersion(releaseVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release version %s: %w", releaseVersion, err)
+	}
+	major := v.Segments()[0]
+
+	suffixMajor, hasSuffix := modulePathMajorVersion(modulePath)
+	switch {
+	case major >= 2 && !hasSuffix:
+		return nil, fmt.Errorf("go module %s must have a /v%d suffix to be released as version %s", modulePath, major, releaseVersion)
+	case major >= 2 && suffixMajor != major:
+		return nil, fmt.Errorf("go module %s has a v%d major version suffix but is being released as version %s", modulePath, suffixMajor, releaseVersion)
+	case major < 2 && hasSuffix && suffixMajor >= 2:
+		return nil, fmt.Errorf("go module %s has a v%d major version suffix but is being released as version %s", modulePath, suffixMajor, releaseVersion)
+	}
+
+	return &GoModuleRelease{
+		ModulePath: modulePath,
+		ModuleDir:  filepath.ToSlash(moduleDir),
+		Tag:        publishtargets.ForTarget("go").ReleaseTag(moduleDir, releaseVersion),
+	}, nil
+}
+
+// findGoModDir returns the directory of the go.mod of the Go target at
+// targetPath, which is the target path or its closest parent within repoDir.
+func findGoModDir(repoDir, targetPath string) (string, error) {
+	dir := filepath.Clean(targetPath)
+	if filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("go target path %s is not within the repository", targetPath)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(repoDir, dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		if dir == "." {
+			return "", fmt.Errorf("no go.mod found for go target in %s", targetPath)
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func parseModulePath(goMod []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(goMod))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t' && rest[0] != '"') {
+			continue
+		}
+
+		modulePath := strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(modulePath); err == nil {
+			modulePath = unquoted
+		}
+		if modulePath == "" {
+			break
+		}
+
+		return modulePath, nil
+	}
+
+	return "", fmt.Errorf("no module directive found in go.mod")
+}
+
+func modulePathMajorVersion(modulePath string) (int, bool) {
+	regex := majorVersionSuffixRegex
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		regex = gopkgInSuffixRegex
+	}
+
+	matches := regex.FindStringSubmatch(modulePath)
+	if matches == nil {
+		return 0, false
+	}
+
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return major, true
+}
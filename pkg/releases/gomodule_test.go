@@ -0,0 +1,153 @@
+package releases_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGoRelease(t *testing.T) {
+	tests := []struct {
+		name           string
+		goModDir       string
+		goMod          string
+		targetPath     string
+		version        string
+		wantModulePath string
+		wantTag        string
+		wantErr        string
+	}{
+		{
+			name:           "root module",
+			goModDir:       ".",
+			goMod:          "module github.com/org/sdk\n\ngo 1.22\n",
+			targetPath:     ".",
+			version:        "1.2.3",
+			wantModulePath: "github.com/org/sdk",
+			wantTag:        "v1.2.3",
+		},
+		{
+			name:           "nested module",
+			goModDir:       "go",
+			goMod:          "module github.com/org/sdk/go\n",
+			targetPath:     "go",
+			version:        "0.4.0",
+			wantModulePath: "github.com/org/sdk/go",
+			wantTag:        "go/v0.4.0",
+		},
+		{
+			name:           "major version suffix",
+			goModDir:       ".",
+			goMod:          "module github.com/org/sdk/v2 // comment\n",
+			targetPath:     ".",
+			version:        "2.0.0",
+			wantModulePath: "github.com/org/sdk/v2",
+			wantTag:        "v2.0.0",
+		},
+		{
+			name:           "major version subdirectory",
+			goModDir:       "sdk/v3",
+			goMod:          "module \"github.com/org/repo/sdk/v3\"\n",
+			targetPath:     "sdk/v3",
+			version:        "3.1.0",
+			wantModulePath: "github.com/org/repo/sdk/v3",
+			wantTag:        "sdk/v3.1.0",
+		},
+		{
+			name:           "go.mod in parent of target",
+			goModDir:       "sdk",
+			goMod:          "module github.com/org/repo/sdk\n",
+			targetPath:     "sdk/models",
+			version:        "1.0.0",
+			wantModulePath: "github.com/org/repo/sdk",
+			wantTag:        "sdk/v1.0.0",
+		},
+		{
+			name:           "gopkg.in module",
+			goModDir:       ".",
+			goMod:          "module gopkg.in/org/sdk.v2\n",
+			targetPath:     ".",
+			version:        "2.5.0",
+			wantModulePath: "gopkg.in/org/sdk.v2",
+			wantTag:        "v2.5.0",
+		},
+		{
+			name:       "missing major version suffix",
+			goModDir:   ".",
+			goMod:      "module github.com/org/sdk\n",
+			targetPath: ".",
+			version:    "2.0.0",
+			wantErr:    "must have a /v2 suffix",
+		},
+		{
+			name:       "mismatched major version suffix",
+			goModDir:   ".",
+			goMod:      "module github.com/org/sdk/v2\n",
+			targetPath: ".",
+			version:    "3.0.0",
+			wantErr:    "has a v2 major version suffix",
+		},
+		{
+			name:       "suffix on v1 release",
+			goModDir:   ".",
+			goMod:      "module github.com/org/sdk/v2\n",
+			targetPath: ".",
+			version:    "1.5.0",
+			wantErr:    "has a v2 major version suffix",
+		},
+		{
+			name:       "no module directive",
+			goModDir:   ".",
+			goMod:      "go 1.22\n",
+			targetPath: ".",
+			version:    "1.0.0",
+			wantErr:    "no module directive",
+		},
+		{
+			name:       "no go.mod",
+			targetPath: "go",
+			version:    "1.0.0",
+			wantErr:    "no go.mod found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(repoDir, tt.targetPath), 0o755))
+			if tt.goMod != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(repoDir, tt.goModDir), 0o755))
+				require.NoError(t, os.WriteFile(filepath.Join(repoDir, tt.goModDir, "go.mod"), []byte(tt.goMod), 0o644))
+			}
+
+			got, err := releases.ValidateGoRelease(repoDir, tt.targetPath, tt.version)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantModulePath, got.ModulePath)
+			assert.Equal(t, tt.wantTag, got.Tag)
+		})
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+
+	// The go target generates into a subdirectory of the module in sdk/v2
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "repo", "sdk", "v2", "gen"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "repo", "sdk", "v2", "go.mod"), []byte("module github.com/org/repo/sdk/v2\n"), 0o644))
+
+	assert.Equal(t, "sdk/v2", releases.PackagePath("go", "sdk/v2/gen"))
+	assert.Equal(t, "sdk/v2/gen", releases.PackagePath("typescript", "sdk/v2/gen"))
+
+	// Without a go.mod, the target path is used
+	assert.Equal(t, "other", releases.PackagePath("go", "other"))
+}
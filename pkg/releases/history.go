@@ -0,0 +1,320 @@
+package releases
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
+	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+)
+
+const releasesHistoryFile = "releases.jsonl"
+
+// GetReleasesHistoryPath returns the location of the structured releases
+// history, which records one JSON encoded ReleasesInfo per line.
+func GetReleasesHistoryPath(dir string) string {
+	return path.Join(environment.GetWorkspace(), "repo", dir, ".speakeasy", releasesHistoryFile)
+}
+
+// ReadReleasesHistory reads every release recorded in the structured releases
+// history for the given directory, oldest first.
+func ReadReleasesHistory(dir string) ([]ReleasesInfo, error) {
+	historyPath := GetReleasesHistoryPath(dir)
+
+	logging.Debug("Reading releases history at %s", historyPath)
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading releases history: %w", err)
+	}
+
+	return ParseReleasesHistory(data)
+}
+
+func ParseReleasesHistory(data []byte) ([]ReleasesInfo, error) {
+	history := []ReleasesInfo{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var info ReleasesInfo
+		if err := json.Unmarshal(scanner.Bytes(), &info); err != nil {
+			return nil, fmt.Errorf("error parsing releases history line %d: %w", line, err)
+		}
+
+		history = append(history, info)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading releases history: %w", err)
+	}
+
+	return history, nil
+}
+
+// MigrateReleasesFile converts an existing RELEASES.md into the structured
+// releases history. It is a no-op if the history already exists or there is
+// no RELEASES.md to migrate.
+func MigrateReleasesFile(dir string) error {
+	if _, err := os.Stat(GetReleasesHistoryPath(dir)); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(GetReleasesPath(dir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading releases file: %w", err)
+	}
+
+	history := ParseReleasesFile(string(data))
+
+	logging.Info("Migrating %d entries from %s to %s", len(history), GetReleasesPath(dir), GetReleasesHistoryPath(dir))
+
+	return writeReleasesHistory(history, dir)
+}
+
+// ParseReleasesFile parses every release in a RELEASES.md file, oldest first.
+// Text that cannot be parsed as a release, such as a header or notes, is kept
+// verbatim in entries of its own, so that rendering the entries keeps it in
+// place.
+func ParseReleasesFile(data string) []ReleasesInfo {
+	sections := strings.Split(data, "\n\n")
+
+	history := []ReleasesInfo{}
+	var previousRelease *string
+	for i := range sections {
+		raw := sections[i]
+		if i > 0 {
+			raw = "\n\n" + raw
+		}
+		if raw == "" {
+			continue
+		}
+
+		info, err := parseRelease(sections[i], previousRelease)
+		if strings.TrimSpace(sections[i]) == "" || err != nil {
+			if strings.TrimSpace(sections[i]) != "" {
+				logging.Info("Keeping unrecognized section in releases file as is: %q", firstLine(sections[i]))
+			}
+
+			// Consecutive unrecognized sections are kept together
+			if n := len(history); n > 0 && !history[n-1].isRelease() {
+				history[n-1].Raw += raw
+			} else {
+				history = append(history, ReleasesInfo{Raw: raw})
+			}
+			continue
+		}
+
+		history = append(history, *info)
+		previousRelease = &sections[i]
+	}
+
+	return history
+}
+
+// YankRelease appends an entry to the releases history recording that the
+// given version of a language was rolled back, and re-renders RELEASES.md
+// without it.
+func YankRelease(dir, lang string, info LanguageReleaseInfo) error {
+	history, err := loadReleasesHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	history = append(history, ReleasesInfo{
+		ReleaseTitle: environment.GetInvokeTime().Format("2006-01-02 15:04:05"),
+		Languages:    map[string]LanguageReleaseInfo{lang: info},
+		Yanked:       true,
+	})
+
+	if err := writeReleasesHistory(history, dir); err != nil {
+		return err
+	}
+
+	return writeReleasesFile(history, dir)
+}
+
+// RenderReleases renders the releases history in the RELEASES.md format.
+// Yanked versions are omitted. Text migrated from RELEASES.md that isn't a
+// release is rendered as it was written.
+func RenderReleases(history []ReleasesInfo) string {
+	var sb strings.Builder
+	for _, info := range effectiveReleases(history) {
+		if info.isRelease() {
+			sb.WriteString(info.String())
+		} else {
+			sb.WriteString(info.Raw)
+		}
+	}
+
+	return sb.String()
+}
+
+// LatestRelease returns the most recent release in the history, ignoring
+// yanked versions. Previous versions that were not recorded with the release
+// are filled in from earlier releases of the same language.
+func LatestRelease(history []ReleasesInfo) (*ReleasesInfo, error) {
+	history = onlyReleases(effectiveReleases(history))
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no releases found in releases history")
+	}
+
+	latest := withPreviousVersions(history[len(history)-1], history[:len(history)-1])
+
+	return &latest, nil
+}
+
+func onlyReleases(history []ReleasesInfo) []ReleasesInfo {
+	releases := make([]ReleasesInfo, 0, len(history))
+	for _, info := range history {
+		if info.isRelease() {
+			releases = append(releases, info)
+		}
+	}
+
+	return releases
+}
+
+func loadReleasesHistory(dir string) ([]ReleasesInfo, error) {
+	if err := MigrateReleasesFile(dir); err != nil {
+		return nil, err
+	}
+
+	history, err := ReadReleasesHistory(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []ReleasesInfo{}, nil
+		}
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func writeReleasesHistory(history []ReleasesInfo, dir string) error {
+	historyPath := GetReleasesHistoryPath(dir)
+
+	var buf bytes.Buffer
+	for _, info := range history {
+		line, err := json.Marshal(info)
+		if err != nil {
+			return fmt.Errorf("error encoding releases history: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(path.Dir(historyPath), 0o755); err != nil {
+		return fmt.Errorf("error creating releases history directory: %w", err)
+	}
+
+	if err := os.WriteFile(historyPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("error writing releases history: %w", err)
+	}
+
+	return nil
+}
+
+func withPreviousVersions(info ReleasesInfo, history []ReleasesInfo) ReleasesInfo {
+	if len(info.Languages) == 0 {
+		return info
+	}
+
+	languages := make(map[string]LanguageReleaseInfo, len(info.Languages))
+	for lang, langInfo := range info.Languages {
+		if langInfo.PreviousVersion == "" {
+			langInfo.PreviousVersion = previousVersion(lang, langInfo.Version, history)
+		}
+		languages[lang] = langInfo
+	}
+	info.Languages = languages
+
+	return info
+}
+
+// effectiveReleases removes yanked entries from the history, along with the
+// versions they yanked from earlier releases. Releases left without any
+// languages are dropped.
+func effectiveReleases(history []ReleasesInfo) []ReleasesInfo {
+	yanked := map[string]map[string]bool{}
+	for _, info := range history {
+		if !info.Yanked {
+			continue
+		}
+		for lang, langInfo := range info.Languages {
+			if yanked[lang] == nil {
+				yanked[lang] = map[string]bool{}
+			}
+			yanked[lang][langInfo.Version] = true
+		}
+	}
+
+	if len(yanked) == 0 {
+		return history
+	}
+
+	effective := make([]ReleasesInfo, 0, len(history))
+	for _, info := range history {
+		if info.Yanked {
+			continue
+		}
+
+		languages := make(map[string]LanguageReleaseInfo, len(info.Languages))
+		for lang, langInfo := range info.Languages {
+			if !yanked[lang][langInfo.Version] {
+				languages[lang] = langInfo
+			}
+		}
+		if len(languages) == 0 && len(info.Languages) > 0 {
+			continue
+		}
+		if len(languages) != len(info.Languages) {
+			info.Languages = languages
+
+			generated := make(map[string]GenerationInfo, len(info.LanguagesGenerated))
+			for lang, genInfo := range info.LanguagesGenerated {
+				if !yanked[lang][genInfo.Version] {
+					generated[lang] = genInfo
+				}
+			}
+			info.LanguagesGenerated = generated
+		}
+
+		effective = append(effective, info)
+	}
+
+	return effective
+}
+
+func previousVersion(lang, currentVersion string, history []ReleasesInfo) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if previous, ok := history[i].Languages[lang]; ok && previous.Version != currentVersion {
+			return previous.Version
+		}
+	}
+
+	return ""
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,251 @@
+package releases_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/speakeasy-api/sdk-generation-action/pkg/releases"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRelease(title, version string) releases.ReleasesInfo {
+	return releases.ReleasesInfo{
+		ReleaseTitle:      title,
+		DocVersion:        "1.0.0",
+		DocLocation:       "https://example.com/openapi.yaml",
+		SpeakeasyVersion:  "1.500.0",
+		GenerationVersion: "2.600.0",
+		Languages: map[string]releases.LanguageReleaseInfo{
+			"typescript": {
+				PackageName: "@org/package",
+				Path:        "typescript",
+				Version:     version,
+			},
+			"terraform": {
+				PackageName: "org/provider",
+				Path:        "terraform",
+				Version:     version,
+			},
+		},
+		LanguagesGenerated: map[string]releases.GenerationInfo{
+			"typescript": {Path: "typescript", Version: version},
+			"terraform":  {Path: "terraform", Version: version},
+		},
+	}
+}
+
+func setupReleasesWorkspace(t *testing.T) string {
+	t.Helper()
+
+	workspace := t.TempDir()
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	t.Setenv("GITHUB_REPOSITORY", "test/repo")
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "repo"), 0o755))
+
+	return workspace
+}
+
+func TestUpdateReleasesFile_WritesHistoryAndRendersMarkdown(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	first := testRelease("2024-01-01 00:00:00", "1.0.0")
+	second := testRelease("2024-01-02 00:00:00", "1.1.0")
+
+	require.NoError(t, releases.UpdateReleasesFile(first, "."))
+	require.NoError(t, releases.UpdateReleasesFile(second, "."))
+
+	history, err := releases.ReadReleasesHistory(".")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, first, history[0])
+	assert.Equal(t, "1.0.0", history[1].Languages["typescript"].PreviousVersion)
+
+	md, err := os.ReadFile(releases.GetReleasesPath("."))
+	require.NoError(t, err)
+	assert.Equal(t, first.String()+second.String(), string(md))
+
+	latest, err := releases.GetLastReleaseInfo(".")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", latest.Languages["terraform"].Version)
+	assert.Equal(t, "1.0.0", latest.Languages["terraform"].PreviousVersion)
+}
+
+func TestUpdateReleasesFile_MigratesExistingReleasesFile(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	legacy := `
+
+## Version 2.1.2
+### Changes
+Based on:
+- OpenAPI Doc 2.0 https://vesselapi.github.io/yaml/openapi.yaml
+- Speakeasy CLI 0.18.1 https://github.com/speakeasy-api/speakeasy
+### Releases
+- [NPM v2.1.2] https://www.npmjs.com/package/@vesselapi/nodesdk/v/2.1.2 - typescript-client-sdk
+
+## Version 2.1.3
+### Changes
+Based on:
+- OpenAPI Doc 2.0 https://vesselapi.github.io/yaml/openapi.yaml
+- Speakeasy CLI 0.18.2 https://github.com/speakeasy-api/speakeasy
+### Releases
+- [NPM v2.1.3] https://www.npmjs.com/package/@vesselapi/nodesdk/v/2.1.3 - typescript-client-sdk`
+	require.NoError(t, os.WriteFile(releases.GetReleasesPath("."), []byte(legacy), 0o600))
+
+	require.NoError(t, releases.MigrateReleasesFile("."))
+
+	history, err := releases.ReadReleasesHistory(".")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "Version 2.1.2", history[0].ReleaseTitle)
+	assert.Equal(t, "2.1.3", history[1].Languages["typescript"].Version)
+	assert.Equal(t, "@vesselapi/nodesdk", history[1].Languages["typescript"].PackageName)
+
+	// The rendered file must still parse to the same latest release as the legacy file
+	require.NoError(t, releases.UpdateReleasesFile(testRelease("2024-01-03 00:00:00", "2.2.0"), "."))
+
+	history, err = releases.ReadReleasesHistory(".")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "2.1.3", history[2].Languages["typescript"].PreviousVersion)
+
+	md, err := os.ReadFile(releases.GetReleasesPath("."))
+	require.NoError(t, err)
+	parsed, err := releases.ParseReleases(string(md))
+	require.NoError(t, err)
+	assert.Equal(t, "2.2.0", parsed.Languages["typescript"].Version)
+	assert.Len(t, releases.ParseReleasesFile(string(md)), 3)
+}
+
+func TestGetLastReleaseInfo_FallsBackToReleasesFile(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	release := testRelease("2024-01-01 00:00:00", "1.0.0")
+	require.NoError(t, os.WriteFile(releases.GetReleasesPath("."), []byte(release.String()), 0o600))
+
+	info, err := releases.GetLastReleaseInfo(".")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", info.Languages["typescript"].Version)
+
+	_, err = os.Stat(releases.GetReleasesHistoryPath("."))
+	assert.True(t, os.IsNotExist(err), "reading releases should not migrate the releases file")
+}
+
+func TestParseReleasesHistory_InvalidLine(t *testing.T) {
+	_, err := releases.ParseReleasesHistory([]byte("{\"release_title\":\"a\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestYankRelease_RemovesVersionFromLatestAndRenderedReleases(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	first := testRelease("2024-01-01 00:00:00", "1.0.0")
+	second := testRelease("2024-01-02 00:00:00", "1.1.0")
+	require.NoError(t, releases.UpdateReleasesFile(first, "."))
+	require.NoError(t, releases.UpdateReleasesFile(second, "."))
+
+	require.NoError(t, releases.YankRelease(".", "typescript", releases.LanguageReleaseInfo{
+		PackageName: "@org/package",
+		Path:        "typescript",
+		Version:     "1.1.0",
+	}))
+
+	history, err := releases.ReadReleasesHistory(".")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.True(t, history[2].Yanked)
+
+	latest, err := releases.LatestRelease(history)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", latest.Languages["terraform"].Version)
+	assert.NotContains(t, latest.Languages, "typescript")
+
+	md, err := os.ReadFile(releases.GetReleasesPath("."))
+	require.NoError(t, err)
+	assert.NotContains(t, string(md), "[NPM v1.1.0]")
+	assert.Contains(t, string(md), "[NPM v1.0.0]")
+
+	// The next release of the yanked language follows on from the last good version
+	require.NoError(t, releases.UpdateReleasesFile(testRelease("2024-01-03 00:00:00", "1.1.1"), "."))
+	latestInfo, err := releases.GetLastReleaseInfo(".")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", latestInfo.Languages["typescript"].PreviousVersion)
+}
+
+func TestMigrateReleasesFile_KeepsUnrecognizedContent(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	legacy := `# Releases
+
+This file is maintained by hand and by the Speakeasy SDK generation action.
+Please don't edit the sections below.
+
+## 2024-01-01 00:00:00
+### Changes
+Based on:
+- OpenAPI Doc 1.0.0 https://example.com/openapi.yaml
+- Speakeasy CLI 1.200.0 (2.300.0) https://github.com/speakeasy-api/speakeasy
+### Generated
+- [typescript v1.0.0] typescript
+### Releases
+- [NPM v1.0.0] https://www.npmjs.com/package/@org/package/v/1.0.0 - typescript
+
+Notes: 1.0.0 was the first public release.
+
+## 2024-02-01 00:00:00
+### Changes
+Based on:
+- OpenAPI Doc 1.1.0 https://example.com/openapi.yaml
+- Speakeasy CLI 1.210.0 (2.310.0) https://github.com/speakeasy-api/speakeasy
+### Generated
+- [typescript v1.1.0] typescript
+### Releases
+- [NPM v1.1.0] https://www.npmjs.com/package/@org/package/v/1.1.0 - typescript
+`
+	require.NoError(t, os.WriteFile(releases.GetReleasesPath("."), []byte(legacy), 0o600))
+
+	next := testRelease("2024-03-01 00:00:00", "1.2.0")
+	require.NoError(t, releases.UpdateReleasesFile(next, "."))
+
+	md, err := os.ReadFile(releases.GetReleasesPath("."))
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSuffix(legacy, "\n")+next.String(), string(md))
+
+	history, err := releases.ReadReleasesHistory(".")
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	assert.Equal(t, "1.1.0", history[4].Languages["typescript"].PreviousVersion)
+
+	// Only the text that isn't a release is kept verbatim
+	assert.NotEmpty(t, history[0].Raw)
+	assert.Empty(t, history[1].Raw)
+	assert.NotEmpty(t, history[2].Raw)
+	assert.Empty(t, history[3].Raw)
+
+	latest, err := releases.GetLastReleaseInfo(".")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", latest.Languages["typescript"].Version)
+	assert.Empty(t, latest.Raw)
+}
+
+func TestYankRelease_MigratedRelease(t *testing.T) {
+	setupReleasesWorkspace(t)
+
+	first := testRelease("2024-01-01 00:00:00", "1.0.0")
+	second := testRelease("2024-01-02 00:00:00", "1.1.0")
+	header := "# Releases"
+	require.NoError(t, os.WriteFile(releases.GetReleasesPath("."), []byte(header+first.String()+second.String()), 0o600))
+
+	require.NoError(t, releases.YankRelease(".", "typescript", second.Languages["typescript"]))
+
+	md, err := os.ReadFile(releases.GetReleasesPath("."))
+	require.NoError(t, err)
+
+	yanked := second
+	delete(yanked.Languages, "typescript")
+	delete(yanked.LanguagesGenerated, "typescript")
+	assert.Equal(t, header+first.String()+yanked.String(), string(md))
+}
@@ -1,31 +1,34 @@
 package releases
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	version "github.com/hashicorp/go-version"
 	config "github.com/speakeasy-api/sdk-gen-config"
 	"github.com/speakeasy-api/sdk-generation-action/internal/environment"
 	"github.com/speakeasy-api/sdk-generation-action/internal/logging"
+	"github.com/speakeasy-api/sdk-generation-action/internal/publishtargets"
 	"github.com/speakeasy-api/sdk-generation-action/internal/utils"
 )
 
 type LanguageReleaseInfo struct {
-	PackageName     string
-	Path            string
-	Version         string
-	PreviousVersion string
-	URL             string
+	PackageName     string `json:"package_name"`
+	Path            string `json:"path"`
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version,omitempty"`
+	URL             string `json:"url,omitempty"`
 }
 
 type GenerationInfo struct {
-	Version string
-	Path    string
+	Version string `json:"version"`
+	Path    string `json:"path"`
 }
 
 // TargetReleaseNotes maps workflow target name to their specific release content
@@ -47,23 +50,36 @@ func (t TargetReleaseNotes) HasReleaseNotesForTarget(target string) bool {
 }
 
 type ReleasesInfo struct {
-	ReleaseTitle       string
-	DocVersion         string
-	SpeakeasyVersion   string
-	GenerationVersion  string
-	DocLocation        string
-	Languages          map[string]LanguageReleaseInfo
-	LanguagesGenerated map[string]GenerationInfo
+	ReleaseTitle       string                         `json:"release_title"`
+	DocVersion         string                         `json:"doc_version"`
+	SpeakeasyVersion   string                         `json:"speakeasy_version"`
+	GenerationVersion  string                         `json:"generation_version"`
+	DocLocation        string                         `json:"doc_location"`
+	Languages          map[string]LanguageReleaseInfo `json:"languages"`
+	LanguagesGenerated map[string]GenerationInfo      `json:"languages_generated"`
+	// Yanked marks a history entry recording that the versions in Languages
+	// were rolled back, rather than a new release.
+	Yanked bool `json:"yanked,omitempty"`
+	// Raw is the verbatim text migrated from RELEASES.md that isn't a release,
+	// such as a header, rendered in its place so that it is kept. Releases
+	// are always rendered from their fields.
+	Raw string `json:"raw,omitempty"`
+}
+
+// isRelease reports whether the entry is a release, rather than text migrated
+// from RELEASES.md that couldn't be parsed as one.
+func (r ReleasesInfo) isRelease() bool {
+	return r.Raw == ""
 }
 
 func (l LanguageReleaseInfo) IsPrerelease() bool {
-	logging.Info("version is %v ", l.Version)
+	logging.Debug("version is %v ", l.Version)
 	v, err := version.NewVersion(l.Version)
 	if err != nil {
 		logging.Error("error parsing version when deciding if it is a prerelease. Therefore assuming it is not a prerelease. Version is %v. Error details: %v", l.Version, err)
 		return false
 	}
-	logging.Info("prerelease info from go lib %v", v.Prerelease())
+	logging.Debug("prerelease info from go lib %v", v.Prerelease())
 	if v.Prerelease() != "" {
 		// If a prerelease info was found it means its a prerelease
 		return true
@@ -76,7 +92,8 @@ func (r ReleasesInfo) String() string {
 	generationOutput := []string{}
 	releasesOutput := []string{}
 
-	for lang, info := range r.LanguagesGenerated {
+	for _, lang := range sortedKeys(r.LanguagesGenerated) {
+		info := r.LanguagesGenerated[lang]
 		generationOutput = append(generationOutput, fmt.Sprintf("- [%s v%s] %s", lang, info.Version, info.Path))
 	}
 
@@ -84,63 +101,17 @@ func (r ReleasesInfo) String() string {
 		generationOutput = append([]string{"\n### Generated"}, generationOutput...)
 	}
 
-	for lang, info := range r.Languages {
-		pkgID := ""
-		pkgURL := ""
-		switch lang {
-		case "cli":
-			pkgID = "CLI"
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-			tag := fmt.Sprintf("v%s", info.Version)
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		case "go":
-			pkgID = "Go"
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-			tag := fmt.Sprintf("v%s", info.Version)
-			if info.Path != "." {
-				tag = fmt.Sprintf("%s/%s", info.Path, tag)
-			}
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		case "typescript":
-			pkgID = "NPM"
-			pkgURL = fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", info.PackageName, info.Version)
-		case "python":
-			pkgID = "PyPI"
-			pkgURL = fmt.Sprintf("https://pypi.org/project/%s/%s", info.PackageName, info.Version)
-		case "php":
-			pkgID = "Composer"
-			pkgURL = fmt.Sprintf("https://packagist.org/packages/%s#v%s", info.PackageName, info.Version)
-		case "terraform":
-			pkgID = "Terraform"
-			pkgURL = fmt.Sprintf("https://registry.terraform.io/providers/%s/%s", info.PackageName, info.Version)
-		case "java":
-			pkgID = "Maven Central"
-			lastDotIndex := strings.LastIndex(info.PackageName, ".")
-			groupID := info.PackageName[:lastDotIndex]      // everything before last occurrence of '.'
-			artifactID := info.PackageName[lastDotIndex+1:] // everything after last occurrence of '.'
-			pkgURL = fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, info.Version)
-		case "ruby":
-			pkgID = "Ruby Gems"
-			pkgURL = fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", info.PackageName, info.Version)
-		case "csharp":
-			pkgID = "NuGet"
-			pkgURL = fmt.Sprintf("https://www.nuget.org/packages/%s/%s", info.PackageName, info.Version)
-		case "swift":
-			pkgID = "Swift Package Manager"
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-
-			tag := fmt.Sprintf("v%s", info.Version)
-			if info.Path != "." {
-				tag = fmt.Sprintf("%s/%s", info.Path, tag)
-			}
+	for _, lang := range sortedKeys(r.Languages) {
+		info := r.Languages[lang]
+		registry := publishtargets.ForTarget(lang)
+		pkgURL := registry.PackageURL(publishtargets.Package{
+			Name:    info.PackageName,
+			Version: info.Version,
+			Path:    PackagePath(lang, info.Path),
+		})
 
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		}
-
-		if pkgID != "" {
-			releasesOutput = append(releasesOutput, fmt.Sprintf("- [%s v%s] %s - %s", pkgID, info.Version, pkgURL, info.Path))
+		if registry.Label != "" {
+			releasesOutput = append(releasesOutput, fmt.Sprintf("- [%s v%s] %s - %s", registry.Label, info.Version, pkgURL, info.Path))
 		}
 	}
 
@@ -148,27 +119,52 @@ func (r ReleasesInfo) String() string {
 		releasesOutput = append([]string{"\n### Releases"}, releasesOutput...)
 	}
 
+	speakeasyVersion := r.SpeakeasyVersion
+	if r.GenerationVersion != "" {
+		speakeasyVersion = fmt.Sprintf("%s (%s)", r.SpeakeasyVersion, r.GenerationVersion)
+	}
+
 	return fmt.Sprintf(`%s## %s
 ### Changes
 Based on:
 - OpenAPI Doc %s %s
-- Speakeasy CLI %s (%s) https://github.com/speakeasy-api/speakeasy%s%s`, "\n\n", r.ReleaseTitle, r.DocVersion, r.DocLocation, r.SpeakeasyVersion, r.GenerationVersion, strings.Join(generationOutput, "\n"), strings.Join(releasesOutput, "\n"))
+- Speakeasy CLI %s https://github.com/speakeasy-api/speakeasy%s%s`, "\n\n", r.ReleaseTitle, r.DocVersion, r.DocLocation, speakeasyVersion, strings.Join(generationOutput, "\n"), strings.Join(releasesOutput, "\n"))
 }
 
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UpdateReleasesFile records the release in the structured releases history and
+// re-renders RELEASES.md from it. An existing RELEASES.md without a history file
+// is migrated first so that no previous releases are lost.
 func UpdateReleasesFile(releaseInfo ReleasesInfo, dir string) error {
-	releasesPath := GetReleasesPath(dir)
+	history, err := loadReleasesHistory(dir)
+	if err != nil {
+		return err
+	}
 
-	logging.Debug("Updating releases file at %s", releasesPath)
+	history = append(history, withPreviousVersions(releaseInfo, effectiveReleases(history)))
 
-	f, err := os.OpenFile(releasesPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
-	if err != nil {
-		logging.Error("error while opening file: %s", err.Error())
-		return fmt.Errorf("error opening releases file: %w", err)
+	if err := writeReleasesHistory(history, dir); err != nil {
+		return err
 	}
-	defer f.Close()
 
-	_, err = f.WriteString(releaseInfo.String())
-	if err != nil {
+	return writeReleasesFile(history, dir)
+}
+
+func writeReleasesFile(history []ReleasesInfo, dir string) error {
+	releasesPath := GetReleasesPath(dir)
+
+	logging.Debug("Updating releases file at %s", releasesPath)
+
+	if err := os.WriteFile(releasesPath, []byte(RenderReleases(history)), 0o600); err != nil {
+		logging.Error("error while writing file: %s", err.Error())
 		return fmt.Errorf("error writing to releases file: %w", err)
 	}
 
@@ -178,19 +174,19 @@ func UpdateReleasesFile(releaseInfo ReleasesInfo, dir string) error {
 var (
 	releaseInfoRegex        = regexp.MustCompile(`(?s)## (.*?)\n### Changes\nBased on:\n- OpenAPI Doc (.*?) (.*?)\n- Speakeasy CLI (.*?) (\((.*?)\))?.*?`)
 	generatedLanguagesRegex = regexp.MustCompile(`- \[([a-z]+) v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (.*)`)
-	npmReleaseRegex         = regexp.MustCompile(`- \[NPM v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/www\.npmjs\.com\/package\/(.*?)\/v\/\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
-	pypiReleaseRegex        = regexp.MustCompile(`- \[PyPI v(\d+\.\d+\.\d+(?:-?\w+(?:\.\w+)*)?)] (https:\/\/pypi\.org\/project\/(.*?)\/\d+\.\d+\.\d+(?:-?\w+(?:\.\w+)*)?) - (.*)`)
-	goReleaseRegex          = regexp.MustCompile(`- \[Go v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/(github.com\/.*?)\/releases\/tag\/.*?\/?v\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
-	composerReleaseRegex    = regexp.MustCompile(`- \[Composer v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/packagist\.org\/packages\/(.*?)#v\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
-	mavenReleaseRegex       = regexp.MustCompile(`- \[Maven Central v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/central\.sonatype\.com\/artifact\/(.*?)\/(.*?)\/.*?) - (.*)`)
-	terraformReleaseRegex   = regexp.MustCompile(`- \[Terraform v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/registry\.terraform\.io\/providers\/(.*?)\/(.*?)\/.*?) - (.*)`)
-	rubyGemReleaseRegex     = regexp.MustCompile(`- \[Ruby Gems v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/rubygems\.org\/gems\/(.*?)\/versions\/.*?) - (.*)`)
-	nugetReleaseRegex       = regexp.MustCompile(`- \[NuGet v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/www\.nuget\.org\/packages\/(.*?)\/\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
-	swiftReleaseRegex       = regexp.MustCompile(`- \[Swift Package Manager v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/(github.com\/.*?)\/releases\/tag\/.*?\/?v\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
-	cliReleaseRegex         = regexp.MustCompile(`- \[CLI v(\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?)] (https:\/\/(github.com\/.*?)\/releases\/tag\/.*?\/?v\d+\.\d+\.\d+(?:-\w+(?:\.\w+)*)?) - (.*)`)
 )
 
+// GetLastReleaseInfo returns the most recent release for the given directory,
+// preferring the structured releases history over parsing RELEASES.md.
 func GetLastReleaseInfo(dir string) (*ReleasesInfo, error) {
+	history, err := ReadReleasesHistory(dir)
+	if err == nil {
+		return LatestRelease(history)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
 	releasesPath := GetReleasesPath(dir)
 
 	logging.Debug("Reading releases file at %s", releasesPath)
@@ -264,48 +260,11 @@ func GetTargetSpecificReleaseNotes(path string) (TargetReleaseNotes, error) {
 		notes := ""
 		partOfFirstLine := fmt.Sprintf("%s %s", utils.GetPackageName(lang, &info), lockFile.Management.ReleaseVersion)
 
-		pkgURL := ""
-		switch lang {
-		case "cli":
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-			tag := fmt.Sprintf("v%s", info.Version)
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		case "go":
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-			tag := fmt.Sprintf("v%s", info.Version)
-			if path != "." {
-				tag = fmt.Sprintf("%s/%s", path, tag)
-			}
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		case "typescript":
-			pkgURL = fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", packageName, version)
-		case "python":
-			pkgURL = fmt.Sprintf("https://pypi.org/project/%s/%s", packageName, version)
-		case "php":
-			pkgURL = fmt.Sprintf("https://packagist.org/packages/%s#v%s", packageName, version)
-		case "terraform":
-			pkgURL = fmt.Sprintf("https://registry.terraform.io/providers/%s/%s", packageName, version)
-		case "java":
-			lastDotIndex := strings.LastIndex(packageName, ".")
-			groupID := packageName[:lastDotIndex]      // everything before last occurrence of '.'
-			artifactID := packageName[lastDotIndex+1:] // everything after last occurrence of '.'
-			pkgURL = fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s/%s", groupID, artifactID, version)
-		case "ruby":
-			pkgURL = fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", packageName, version)
-		case "csharp":
-			pkgURL = fmt.Sprintf("https://www.nuget.org/packages/%s/%s", packageName, version)
-		case "swift":
-			repoPath := os.Getenv("GITHUB_REPOSITORY")
-
-			tag := fmt.Sprintf("v%s", info.Version)
-			if path != "." {
-				tag = fmt.Sprintf("%s/%s", path, tag)
-			}
-
-			pkgURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoPath, tag)
-		}
+		pkgURL := publishtargets.ForTarget(lang).PackageURL(publishtargets.Package{
+			Name:    packageName,
+			Version: version,
+			Path:    PackagePath(lang, path),
+		})
 
 		firstLine := fmt.Sprintf("\n[%s](%s)", partOfFirstLine, pkgURL)
 		notes += firstLine
@@ -334,6 +293,10 @@ func ParseReleases(data string) (*ReleasesInfo, error) {
 		previousRelease = &releases[len(releases)-2]
 	}
 
+	return parseRelease(lastRelease, previousRelease)
+}
+
+func parseRelease(lastRelease string, previousRelease *string) (*ReleasesInfo, error) {
 	matches := releaseInfoRegex.FindStringSubmatch(lastRelease)
 
 	if len(matches) < 5 {
@@ -367,152 +330,28 @@ func ParseReleases(data string) (*ReleasesInfo, error) {
 		}
 	}
 
-	npmMatches := npmReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(npmMatches) == 5 {
-		info.Languages["typescript"] = LanguageReleaseInfo{
-			Version:     npmMatches[1],
-			URL:         npmMatches[2],
-			PackageName: npmMatches[3],
-			Path:        npmMatches[4],
-		}
-	}
-
-	pypiMatches := pypiReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(pypiMatches) == 5 {
-		info.Languages["python"] = LanguageReleaseInfo{
-			Version:     pypiMatches[1],
-			URL:         pypiMatches[2],
-			PackageName: pypiMatches[3],
-			Path:        pypiMatches[4],
+	for _, lang := range publishtargets.Targets() {
+		registry := publishtargets.ForTarget(lang)
+		pkg, url, ok := registry.ParseRelease(lastRelease)
+		if !ok {
+			continue
 		}
-	}
-
-	goMatches := goReleaseRegex.FindStringSubmatch(lastRelease)
 
-	if len(goMatches) == 5 {
-		packageName := goMatches[3]
-		path := goMatches[4]
-
-		if path != "." {
-			packageName = fmt.Sprintf("%s/%s", packageName, strings.TrimPrefix(path, "./"))
-		}
-
-		info.Languages["go"] = LanguageReleaseInfo{
-			Version:     goMatches[1],
-			URL:         goMatches[2],
-			PackageName: packageName,
-			Path:        path,
-		}
-	}
-
-	composerMatches := composerReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(composerMatches) == 5 {
-		info.Languages["php"] = LanguageReleaseInfo{
-			Version:     composerMatches[1],
-			URL:         composerMatches[2],
-			PackageName: composerMatches[3],
-			Path:        composerMatches[4],
-		}
-	}
-
-	mavenMatches := mavenReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(mavenMatches) == 6 {
-		groupID := mavenMatches[3]
-		artifact := mavenMatches[4]
-		info.Languages["java"] = LanguageReleaseInfo{
-			Version:     mavenMatches[1],
-			URL:         mavenMatches[2],
-			PackageName: fmt.Sprintf(`%s.%s`, groupID, artifact),
-			Path:        mavenMatches[5],
-		}
-	}
-
-	terraformMatches := terraformReleaseRegex.FindStringSubmatch(lastRelease)
-	if len(terraformMatches) == 6 {
 		languageInfo := LanguageReleaseInfo{
-			Version:     terraformMatches[1],
-			URL:         terraformMatches[2],
-			PackageName: fmt.Sprintf("%s/%s", terraformMatches[3], terraformMatches[4]),
-			Path:        terraformMatches[5],
-		}
-
-		if previousRelease != nil {
-			previousReleaseTerraform := terraformReleaseRegex.FindStringSubmatch(*previousRelease)
-			if len(previousReleaseTerraform) == 6 {
-				languageInfo.PreviousVersion = previousReleaseTerraform[1]
-			}
-		}
-		info.Languages["terraform"] = languageInfo
-
-	}
-	rubyGemsMatches := rubyGemReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(rubyGemsMatches) == 5 {
-		info.Languages["ruby"] = LanguageReleaseInfo{
-			Version:     rubyGemsMatches[1],
-			URL:         rubyGemsMatches[2],
-			PackageName: rubyGemsMatches[3],
-			Path:        rubyGemsMatches[4],
-		}
-	}
-
-	nugetMatches := nugetReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(nugetMatches) == 5 {
-		info.Languages["csharp"] = LanguageReleaseInfo{
-			Version:     nugetMatches[1],
-			URL:         nugetMatches[2],
-			PackageName: nugetMatches[3],
-			Path:        nugetMatches[4],
-		}
-	}
-
-	swiftMatches := swiftReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(swiftMatches) == 5 {
-		packageName := swiftMatches[3]
-		path := swiftMatches[4]
-
-		if path != "." {
-			packageName = fmt.Sprintf("%s/%s", packageName, strings.TrimPrefix(path, "./"))
-		}
-
-		info.Languages["swift"] = LanguageReleaseInfo{
-			Version:     swiftMatches[1],
-			URL:         swiftMatches[2],
-			PackageName: packageName,
-			Path:        path,
-		}
-	}
-
-	cliMatches := cliReleaseRegex.FindStringSubmatch(lastRelease)
-
-	if len(cliMatches) == 5 {
-		packageName := cliMatches[3]
-		path := cliMatches[4]
-		languageInfo := LanguageReleaseInfo{
-			Version:     cliMatches[1],
-			URL:         cliMatches[2],
-			PackageName: packageName,
-			Path:        path,
-		}
-
-		if path != "." {
-			languageInfo.PackageName = fmt.Sprintf("%s/%s", packageName, strings.TrimPrefix(path, "./"))
+			Version:     pkg.Version,
+			URL:         url,
+			PackageName: pkg.Name,
+			Path:        pkg.Path,
 		}
 
-		if previousRelease != nil {
-			previousReleaseCLI := cliReleaseRegex.FindStringSubmatch(*previousRelease)
-			if len(previousReleaseCLI) == 5 {
-				languageInfo.PreviousVersion = previousReleaseCLI[1]
+		// goreleaser needs the previous tag to generate its changelog
+		if previousRelease != nil && (lang == "terraform" || lang == "cli") {
+			if previousPkg, _, ok := registry.ParseRelease(*previousRelease); ok {
+				languageInfo.PreviousVersion = previousPkg.Version
 			}
 		}
 
-		info.Languages["cli"] = languageInfo
+		info.Languages[lang] = languageInfo
 	}
 
 	return info, nil
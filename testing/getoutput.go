@@ -18,13 +18,23 @@ func main() {
 	}
 
 	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		parts := strings.Split(line, "=")
-		if len(parts) != 2 {
+	for i := 0; i < len(lines); i++ {
+		// Multi-line values are written as name<<delimiter, the value's lines and the delimiter
+		if name, delimiter, ok := strings.Cut(lines[i], "<<"); ok && !strings.Contains(name, "=") {
+			var value []string
+			for i++; i < len(lines) && lines[i] != delimiter; i++ {
+				value = append(value, lines[i])
+			}
+			if name == *outputName {
+				fmt.Print(strings.Join(value, "\n"))
+				break
+			}
 			continue
 		}
-		if parts[0] == *outputName {
-			fmt.Print(parts[1])
+
+		name, value, ok := strings.Cut(lines[i], "=")
+		if ok && name == *outputName {
+			fmt.Print(value)
 			break
 		}
 	}